@@ -0,0 +1,39 @@
+package main
+
+import (
+	"context"
+	"encoding/json"
+	"flag"
+	"io"
+
+	"github.com/thisiscetin/podpredict/internal/eval"
+	"github.com/thisiscetin/podpredict/internal/fetcher"
+	"github.com/thisiscetin/podpredict/internal/model"
+)
+
+// runEval implements the "eval" subcommand: it fetches the training data,
+// backtests models built by newModel with k-fold and walk-forward evaluation and writes
+// the metrics to out as JSON.
+func runEval(ctx context.Context, f fetcher.Fetcher, newModel model.Factory, args []string, out io.Writer) error {
+	fs := flag.NewFlagSet("eval", flag.ContinueOnError)
+	var opts eval.Options
+	fs.IntVar(&opts.Folds, "k", eval.DefaultFolds, "number of k-fold cross-validation folds")
+	fs.IntVar(&opts.MinTrain, "min-train", 0, "initial walk-forward training rows (default half of the rows)")
+	fs.IntVar(&opts.Horizon, "horizon", 1, "rows scored per walk-forward step")
+	if err := fs.Parse(args); err != nil {
+		return err
+	}
+
+	data, _, err := f.Fetch(ctx)
+	if err != nil {
+		return err
+	}
+	res, err := eval.Run(newModel, data, opts)
+	if err != nil {
+		return err
+	}
+
+	enc := json.NewEncoder(out)
+	enc.SetIndent("", "  ")
+	return enc.Encode(res)
+}
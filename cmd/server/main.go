@@ -3,22 +3,32 @@ package main
 import (
 	"context"
 	"errors"
+	"io"
 	"log"
 	"net/http"
+	"os"
 	"os/signal"
 	"syscall"
 	"time"
 
-	"github.com/google/uuid"
-
 	"github.com/thisiscetin/podpredict/internal/api"
 	"github.com/thisiscetin/podpredict/internal/config"
+	"github.com/thisiscetin/podpredict/internal/fetcher"
+	"github.com/thisiscetin/podpredict/internal/fetcher/csv"
 	"github.com/thisiscetin/podpredict/internal/fetcher/gsheets"
+	"github.com/thisiscetin/podpredict/internal/fetcher/resilient"
 	"github.com/thisiscetin/podpredict/internal/metrics"
 	"github.com/thisiscetin/podpredict/internal/model"
+	"github.com/thisiscetin/podpredict/internal/model/forest"
+	"github.com/thisiscetin/podpredict/internal/model/gbt"
+	"github.com/thisiscetin/podpredict/internal/model/lasso"
 	"github.com/thisiscetin/podpredict/internal/model/linreg"
+	"github.com/thisiscetin/podpredict/internal/model/ridge"
+	"github.com/thisiscetin/podpredict/internal/model/seasonal"
 	"github.com/thisiscetin/podpredict/internal/store"
 	"github.com/thisiscetin/podpredict/internal/store/inmemory"
+	"github.com/thisiscetin/podpredict/internal/store/jsonl"
+	"github.com/thisiscetin/podpredict/internal/store/sqlite"
 )
 
 func main() {
@@ -33,34 +43,74 @@ func main() {
 	}
 
 	// Deps
-	mdl := linreg.NewModel()
-	st := inmemory.NewStore()
+	newModel := newModelFactory(cfg)
+	mdl := newModel()
+	st, err := newStore(ctx, cfg)
+	if err != nil {
+		log.Fatal("store init error: ", err)
+	}
+	if c, ok := st.(io.Closer); ok {
+		defer c.Close()
+	}
 
 	// Fetcher
-	ftc, err := gsheets.NewFetcher(ctx, cfg.CredsJSON, cfg.SpreadsheetID)
+	ftc, err := newFetcher(ctx, cfg)
 	if err != nil {
 		log.Fatal("fetcher init error: ", err)
 	}
 
-	// Fetch → Train
-	mtr, err := ftc.Fetch()
-	if err != nil {
-		log.Fatal("fetch error: ", err)
+	// Subcommands
+	if len(os.Args) > 1 && os.Args[1] == "eval" {
+		if err := runEval(ctx, ftc, newModel, os.Args[2:], os.Stdout); err != nil {
+			log.Fatal("eval error: ", err)
+		}
+		return
 	}
-	if err := mdl.Train(filterDaysWithPods(mtr)); err != nil {
-		log.Fatal("train error: ", err)
+
+	// API (fetches and trains the model) & Server
+	h, err := api.New(ctx, mdl, ftc, st, cfg.FetchTimeout,
+		api.WithMaxRejectRatio(cfg.MaxRejectRatio),
+		api.WithAdminToken(cfg.AdminToken),
+		api.WithMaxBatchSize(cfg.MaxBatchSize),
+		api.WithMaxBodyBytes(int64(cfg.MaxBodyBytes)),
+		api.WithOODPolicy(api.OODPolicy(cfg.OODPolicy)),
+		api.WithModelFactory(newModel),
+	)
+	if err != nil {
+		log.Fatal("api init failed: ", err)
 	}
 
-	// Predict missing pods → Store
-	if err := upsertPredictions(ctx, mdl, st, mtr); err != nil {
+	tr := h.Training()
+	rep := tr.Report
+	log.Printf("ingest %s: read %d rows, accepted %d, rejected %d, warnings %d, duplicate dates %d, gaps %d",
+		rep.Source, rep.RowsRead, rep.RowsAccepted, len(rep.Rejected), len(rep.Warnings), len(rep.DuplicateDates), len(rep.Gaps))
+
+	// Seed actuals and predict missing pods from the training fetch → Store (idempotent)
+	if err := upsertPredictions(ctx, tr.Model, st, tr.Data, tr.Lineage); err != nil {
 		log.Fatal("prediction storage error: ", err)
 	}
 
-	// API & Server
-	h, err := api.New(mdl, ftc, st, cfg.FetchTimeout)
+	// Background retraining
+	sched, err := newSchedule(cfg)
 	if err != nil {
-		log.Fatal("api init failed: ", err)
+		log.Fatal("scheduler init failed: ", err)
 	}
+	if sched != nil {
+		go runScheduler(ctx, sched, func(ctx context.Context) error {
+			sum, err := h.Retrain(ctx)
+			if errors.Is(err, api.ErrRetrainInProgress) {
+				log.Print("scheduled retrain skipped: ", err)
+				return nil
+			}
+			if err != nil {
+				return err
+			}
+			log.Printf("scheduled retrain: model %s trained on %d rows in %dms",
+				sum.ModelVersion, sum.RowsUsed, sum.TrainingDurationMS)
+			return nil
+		})
+	}
+
 	srv := &http.Server{
 		Addr:              cfg.Addr,
 		Handler:           api.Routes(h),
@@ -94,38 +144,112 @@ func main() {
 	_ = srv.Shutdown(shCtx)
 }
 
-func filterDaysWithPods(ms []metrics.Daily) []metrics.Daily {
-	out := make([]metrics.Daily, 0, len(ms))
-	for _, m := range ms {
-		if m.HasPods() {
-			out = append(out, m)
+// newFetcher builds the Fetcher for the configured data source, wrapped
+// with timeouts, retries and a circuit breaker.
+func newFetcher(ctx context.Context, cfg config.Config) (fetcher.Fetcher, error) {
+	var (
+		src fetcher.Fetcher
+		err error
+	)
+	switch cfg.Source {
+	case config.SourceCSV:
+		src, err = csv.NewFetcher(cfg.CSVPath, cfg.Layout)
+	default:
+		src, err = gsheets.NewFetcher(ctx, cfg.CredsJSON, cfg.SpreadsheetID, gsheets.Options{
+			SheetName: cfg.SheetName,
+			Range:     cfg.SheetRange,
+			Layout:    cfg.Layout,
+		})
+	}
+	if err != nil {
+		return nil, err
+	}
+	return resilient.NewFetcher(src, resilient.Options{
+		Timeout:          cfg.FetchTimeout,
+		MaxAttempts:      cfg.FetchMaxAttempts,
+		BaseDelay:        cfg.FetchBackoff,
+		BreakerThreshold: cfg.BreakerThreshold,
+		BreakerCooldown:  cfg.BreakerCooldown,
+	}), nil
+}
+
+// newModelFactory returns the Factory of the configured model.
+func newModelFactory(cfg config.Config) model.Factory {
+	switch cfg.Model {
+	case config.ModelRidge:
+		return func() model.Model {
+			return ridge.NewModel(ridge.Options{Lambda: cfg.ModelLambda, Folds: cfg.ModelFolds})
+		}
+	case config.ModelLasso:
+		return func() model.Model {
+			return lasso.NewModel(lasso.Options{Lambda: cfg.ModelLambda, Folds: cfg.ModelFolds})
+		}
+	case config.ModelGBT:
+		return func() model.Model {
+			return gbt.NewModel(gbt.Options{
+				Trees:               cfg.GBTTrees,
+				MaxDepth:            cfg.GBTMaxDepth,
+				LearningRate:        cfg.GBTLearningRate,
+				EarlyStoppingRounds: cfg.GBTEarlyStoppingRounds,
+				Seed:                int64(cfg.ModelSeed),
+			})
+		}
+	case config.ModelForest:
+		return func() model.Model {
+			return forest.NewModel(forest.Options{Trees: cfg.ForestTrees, Seed: int64(cfg.ModelSeed)})
+		}
+	case config.ModelHoltWinters:
+		return func() model.Model {
+			return seasonal.NewModel(seasonal.Options{Holidays: cfg.Holidays})
 		}
+	default:
+		return linreg.NewModel
 	}
-	return out
 }
 
-func upsertPredictions(ctx context.Context, mdl model.Model, st store.Store, ms []metrics.Daily) error {
+// newStore builds the configured prediction store.
+func newStore(ctx context.Context, cfg config.Config) (store.Store, error) {
+	switch cfg.Store {
+	case config.StoreSQLite:
+		return sqlite.NewStore(ctx, cfg.SQLitePath)
+	case config.StoreJSONL:
+		return jsonl.NewStore(cfg.JSONLPath, jsonl.Options{MaxSize: int64(cfg.JSONLMaxSize)})
+	default:
+		return inmemory.NewStore(), nil
+	}
+}
+
+// upsertPredictions stores every training data row under its date: rows
+// with pods as actuals, the others with pods predicted by mdl for that date
+// (see model.PredictOn), whose lineage they record. Rows before the history
+// a model.Forecaster can forecast are skipped. Records are keyed on date,
+// source and kind, so restarts do not duplicate history.
+func upsertPredictions(
+	ctx context.Context, mdl model.Model, st store.Store, ms []metrics.Daily, lineage store.Lineage,
+) error {
 	for _, m := range ms {
-		fePods, bePods := model.FEPods(0), model.BEPods(0)
 		features := model.FeaturesFromDaily(m)
+		rec := store.Prediction{
+			Timestamp: m.Date,
+			Input:     features,
+			Source:    store.SourceSheet,
+		}
 
 		if !m.HasPods() {
-			fp, bp, err := mdl.Predict(&features)
+			fp, bp, err := model.PredictOn(mdl, m.Date, &features)
+			if errors.Is(err, model.ErrBeforeHistory) {
+				continue // a Forecaster has no out-of-sample value for this day
+			}
 			if err != nil {
 				return err
 			}
-			fePods, bePods = fp, bp
+			rec.FEPods, rec.BEPods, rec.Kind = int(fp), int(bp), store.KindPredicted
+			rec.Lineage = &lineage
 		} else {
-			fePods, bePods = model.FEPods(ptrVal(m.FEPods)), model.BEPods(ptrVal(m.BEPods))
+			rec.FEPods, rec.BEPods, rec.Kind = ptrVal(m.FEPods), ptrVal(m.BEPods), store.KindActual
 		}
 
-		if err := st.Append(ctx, store.Prediction{
-			ID:        uuid.New().String(),
-			Timestamp: m.Date,
-			Input:     features,
-			FEPods:    int(fePods),
-			BEPods:    int(bePods),
-		}); err != nil {
+		if err := st.Upsert(ctx, rec); err != nil {
 			return err
 		}
 	}
@@ -0,0 +1,58 @@
+package main
+
+import (
+	"context"
+	"fmt"
+	"log"
+	"time"
+
+	"github.com/robfig/cron/v3"
+
+	"github.com/thisiscetin/podpredict/internal/config"
+)
+
+// schedule yields the next run time strictly after t.
+type schedule interface {
+	Next(t time.Time) time.Time
+}
+
+// every is a fixed-interval schedule.
+type every time.Duration
+
+func (e every) Next(t time.Time) time.Time { return t.Add(time.Duration(e)) }
+
+// newSchedule builds the retrain schedule from config.
+// It returns nil when background retraining is disabled.
+func newSchedule(cfg config.Config) (schedule, error) {
+	if cfg.RetrainCron != "" {
+		s, err := cron.ParseStandard(cfg.RetrainCron)
+		if err != nil {
+			return nil, fmt.Errorf("invalid %s: %w", config.DefaultEnvVarRetrainCron, err)
+		}
+		return s, nil
+	}
+	if cfg.RetrainInterval > 0 {
+		return every(cfg.RetrainInterval), nil
+	}
+	return nil, nil
+}
+
+// runScheduler calls job at every time yielded by s until ctx is done.
+// Runs never overlap: the next time is computed after a run finishes.
+// Errors are logged and the previous model stays in service.
+func runScheduler(ctx context.Context, s schedule, job func(context.Context) error) {
+	for {
+		next := s.Next(time.Now())
+		t := time.NewTimer(time.Until(next))
+		select {
+		case <-ctx.Done():
+			t.Stop()
+			return
+		case <-t.C:
+		}
+
+		if err := job(ctx); err != nil {
+			log.Printf("scheduled retrain failed, keeping previous model: %v", err)
+		}
+	}
+}
@@ -0,0 +1,127 @@
+// Package accuracy reconciles stored predictions with the actual pods that
+// later appear in the training data, and summarizes their error over time.
+package accuracy
+
+import (
+	"context"
+	"errors"
+	"math"
+	"time"
+
+	"github.com/thisiscetin/podpredict/internal/eval"
+	"github.com/thisiscetin/podpredict/internal/metrics"
+	"github.com/thisiscetin/podpredict/internal/store"
+)
+
+// DefaultWindow is the number of predictions of the rolling error in Summarize.
+const DefaultWindow = 7
+
+// Reconcile matches every day of data that has pods with the prediction
+// stored for that day under each of sources (see store.Key), and records
+// the prediction error on it. Predictions already reconciled with the same
+// actuals are left untouched, so Reconcile is idempotent. It returns the
+// number of predictions updated.
+func Reconcile(ctx context.Context, st store.Store, data []metrics.Daily, sources []string, now time.Time) (int, error) {
+	n := 0
+	for _, d := range data {
+		fe, be, ok := d.Pods()
+		if !ok {
+			continue
+		}
+		for _, src := range sources {
+			key := store.Key{Source: src, Kind: store.KindPredicted, Date: d.Date.UTC().Truncate(24 * time.Hour)}
+			p, err := st.Get(ctx, key.ID())
+			if errors.Is(err, store.ErrNotFound) {
+				continue
+			}
+			if err != nil {
+				return n, err
+			}
+			if r := p.Reconciliation; r != nil && r.ActualFEPods == fe && r.ActualBEPods == be {
+				continue
+			}
+
+			p.Reconciliation = &store.Reconciliation{
+				ActualFEPods: fe,
+				ActualBEPods: be,
+				FEError:      p.FEPods - fe,
+				BEError:      p.BEPods - be,
+				ReconciledAt: now.UTC(),
+			}
+			if err := st.Upsert(ctx, p); err != nil {
+				return n, err
+			}
+			n++
+		}
+	}
+	return n, nil
+}
+
+// Point is the error of one reconciled prediction, with the rolling mean
+// absolute error of the window of reconciled predictions ending at it.
+type Point struct {
+	Date         time.Time `json:"date"`
+	Source       string    `json:"source"`
+	FEError      int       `json:"fe_error"`
+	BEError      int       `json:"be_error"`
+	RollingFEMAE float64   `json:"rolling_fe_mae"`
+	RollingBEMAE float64   `json:"rolling_be_mae"`
+}
+
+// Report summarizes the error of reconciled predictions.
+type Report struct {
+	// Window is the number of predictions in each rolling mean.
+	Window int `json:"window"`
+	// Days is the number of reconciled predictions.
+	Days int `json:"days"`
+	// FE and BE are the error metrics over all reconciled predictions.
+	FE eval.Metrics `json:"fe"`
+	BE eval.Metrics `json:"be"`
+	// Points lists each reconciled prediction in date order.
+	Points []Point `json:"points"`
+}
+
+// Summarize builds a Report from reconciled predictions sorted by date.
+// Predictions without a Reconciliation are ignored. window < 1 selects
+// DefaultWindow.
+func Summarize(preds []store.Prediction, window int) Report {
+	if window < 1 {
+		window = DefaultWindow
+	}
+	rep := Report{Window: window, Points: make([]Point, 0, len(preds))}
+
+	var fePred, feActual, bePred, beActual []float64
+	for _, p := range preds {
+		r := p.Reconciliation
+		if r == nil {
+			continue
+		}
+		fePred, feActual = append(fePred, float64(p.FEPods)), append(feActual, float64(r.ActualFEPods))
+		bePred, beActual = append(bePred, float64(p.BEPods)), append(beActual, float64(r.ActualBEPods))
+		rep.Points = append(rep.Points, Point{
+			Date:    p.Timestamp,
+			Source:  p.Source,
+			FEError: r.FEError,
+			BEError: r.BEError,
+		})
+	}
+
+	// Rolling means over the trailing window of points.
+	var feSum, beSum float64
+	for i := range rep.Points {
+		feSum += math.Abs(float64(rep.Points[i].FEError))
+		beSum += math.Abs(float64(rep.Points[i].BEError))
+		if i >= window {
+			feSum -= math.Abs(float64(rep.Points[i-window].FEError))
+			beSum -= math.Abs(float64(rep.Points[i-window].BEError))
+		}
+		n := float64(min(i+1, window))
+		rep.Points[i].RollingFEMAE = feSum / n
+		rep.Points[i].RollingBEMAE = beSum / n
+	}
+
+	rep.Days = len(rep.Points)
+	rep.FE = eval.Score(fePred, feActual)
+	rep.BE = eval.Score(bePred, beActual)
+	return rep
+}
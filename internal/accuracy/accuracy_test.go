@@ -0,0 +1,106 @@
+package accuracy
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"github.com/thisiscetin/podpredict/internal/metrics"
+	"github.com/thisiscetin/podpredict/internal/store"
+	"github.com/thisiscetin/podpredict/internal/store/inmemory"
+)
+
+var day = time.Date(2025, 11, 28, 0, 0, 0, 0, time.UTC)
+
+func daily(t *testing.T, date time.Time, fe, be *int) metrics.Daily {
+	t.Helper()
+	d, err := metrics.NewDaily(date, 100, 10, 1, fe, be)
+	require.NoError(t, err)
+	return d
+}
+
+func ptr(v int) *int { return &v }
+
+func TestReconcile(t *testing.T) {
+	ctx := context.Background()
+	st := inmemory.NewStore()
+	now := time.Date(2025, 12, 1, 8, 0, 0, 0, time.UTC)
+
+	// Predicted on an earlier run for two days; only the first gets actuals.
+	for i, pods := range []int{5, 4} {
+		require.NoError(t, st.Upsert(ctx, store.Prediction{
+			Timestamp: day.AddDate(0, 0, i),
+			FEPods:    pods,
+			BEPods:    pods,
+			Source:    store.SourceSheet,
+			Kind:      store.KindPredicted,
+		}))
+	}
+	data := []metrics.Daily{
+		daily(t, day, ptr(7), ptr(3)),
+		daily(t, day.AddDate(0, 0, 1), nil, nil),
+		daily(t, day.AddDate(0, 0, 2), ptr(1), ptr(1)), // never predicted
+	}
+
+	n, err := Reconcile(ctx, st, data, []string{store.SourceSheet}, now)
+	require.NoError(t, err)
+	assert.Equal(t, 1, n)
+
+	p, err := st.Get(ctx, store.KeyOf(store.Prediction{Timestamp: day, Source: store.SourceSheet, Kind: store.KindPredicted}).ID())
+	require.NoError(t, err)
+	assert.Equal(t, &store.Reconciliation{
+		ActualFEPods: 7,
+		ActualBEPods: 3,
+		FEError:      -2,
+		BEError:      2,
+		ReconciledAt: now,
+	}, p.Reconciliation)
+
+	// Idempotent: nothing changes on a second run.
+	n, err = Reconcile(ctx, st, data, []string{store.SourceSheet}, now.Add(time.Hour))
+	require.NoError(t, err)
+	assert.Equal(t, 0, n)
+
+	// Corrected actuals are reconciled again.
+	data[0] = daily(t, day, ptr(6), ptr(3))
+	n, err = Reconcile(ctx, st, data, []string{store.SourceSheet}, now)
+	require.NoError(t, err)
+	assert.Equal(t, 1, n)
+}
+
+func TestSummarize(t *testing.T) {
+	var preds []store.Prediction
+	for i, fe := range []int{1, -3, 2, 0} {
+		preds = append(preds, store.Prediction{
+			Timestamp:      day.AddDate(0, 0, i),
+			FEPods:         5 + fe,
+			BEPods:         5,
+			Reconciliation: &store.Reconciliation{ActualFEPods: 5, ActualBEPods: 5, FEError: fe},
+		})
+	}
+	preds = append(preds, store.Prediction{Timestamp: day.AddDate(0, 0, 9)}) // not reconciled
+
+	rep := Summarize(preds, 2)
+	assert.Equal(t, 2, rep.Window)
+	assert.Equal(t, 4, rep.Days)
+	require.Len(t, rep.Points, 4)
+
+	rolling := []float64{1, 2, 2.5, 1}
+	for i, want := range rolling {
+		assert.InDelta(t, want, rep.Points[i].RollingFEMAE, 1e-9, "point %d", i)
+		assert.Zero(t, rep.Points[i].RollingBEMAE)
+	}
+	assert.InDelta(t, 6.0/4, rep.FE.MAE, 1e-9)
+	assert.InDelta(t, 25, rep.FE.UnderProvisionedPct, 1e-9)
+	assert.InDelta(t, 50, rep.FE.OverProvisionedPct, 1e-9)
+}
+
+func TestSummarize_DefaultWindowAndEmpty(t *testing.T) {
+	rep := Summarize(nil, 0)
+	assert.Equal(t, DefaultWindow, rep.Window)
+	assert.Zero(t, rep.Days)
+	assert.NotNil(t, rep.Points)
+}
@@ -0,0 +1,66 @@
+package api
+
+import (
+	"context"
+	"errors"
+	"net/http"
+	"strconv"
+
+	"github.com/thisiscetin/podpredict/internal/accuracy"
+	"github.com/thisiscetin/podpredict/internal/store"
+)
+
+// GET /accuracy[?from=&to=][&window=7]
+// from/to accept RFC 3339 timestamps or YYYY-MM-DD dates (from inclusive, to exclusive).
+// Returns: accuracy.Report of reconciled predictions, with the rolling
+// mean absolute error over the last window reconciled predictions, not
+// calendar days: days without a reconciled prediction are skipped
+func (h *Handler) Accuracy(w http.ResponseWriter, r *http.Request) {
+	if r.Method != http.MethodGet {
+		writeError(w, http.StatusMethodNotAllowed, "method not allowed")
+		return
+	}
+	ctx, cancel := context.WithTimeout(r.Context(), h.timeout)
+	defer cancel()
+
+	window := accuracy.DefaultWindow
+	if v := r.URL.Query().Get("window"); v != "" {
+		n, err := strconv.Atoi(v)
+		if err != nil || n < 1 {
+			writeError(w, http.StatusBadRequest, "invalid window parameter")
+			return
+		}
+		window = n
+	}
+	from, to, err := parseRange(r)
+	if err != nil {
+		writeError(w, http.StatusBadRequest, err.Error())
+		return
+	}
+	q := store.Query{
+		From:       from,
+		To:         to,
+		Kind:       store.KindPredicted,
+		Reconciled: true,
+		Limit:      MaxPageSize,
+	}
+
+	var preds []store.Prediction
+	for {
+		page, err := h.store.List(ctx, q)
+		if errors.Is(err, store.ErrInvalidQuery) {
+			writeError(w, http.StatusBadRequest, err.Error())
+			return
+		}
+		if err != nil {
+			writeError(w, http.StatusInternalServerError, "listing predictions failed: "+err.Error())
+			return
+		}
+		preds = append(preds, page.Items...)
+		if page.NextCursor == "" {
+			break
+		}
+		q.Cursor = page.NextCursor
+	}
+	writeJSON(w, http.StatusOK, accuracy.Summarize(preds, window))
+}
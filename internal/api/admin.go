@@ -0,0 +1,190 @@
+package api
+
+import (
+	"context"
+	"crypto/subtle"
+	"errors"
+	"fmt"
+	"net/http"
+	"strings"
+	"time"
+
+	"github.com/google/uuid"
+	"github.com/thisiscetin/podpredict/internal/accuracy"
+	"github.com/thisiscetin/podpredict/internal/fetcher"
+	"github.com/thisiscetin/podpredict/internal/metrics"
+	"github.com/thisiscetin/podpredict/internal/model"
+	"github.com/thisiscetin/podpredict/internal/store"
+)
+
+// reconcileSources lists the sources whose per-day predictions are
+// reconciled with actual pods on every retrain.
+var reconcileSources = []string{store.SourceSheet, store.SourceForecast}
+
+// ErrRetrainInProgress is returned by Retrain when another retrain is running.
+var ErrRetrainInProgress = errors.New("retrain already in progress")
+
+// RetrainSummary describes the outcome of a successful Retrain.
+type RetrainSummary struct {
+	// ModelVersion identifies the newly trained model.
+	ModelVersion string `json:"model_version"`
+	// DataFingerprint identifies the training data (see metrics.Fingerprint).
+	DataFingerprint string `json:"data_fingerprint"`
+	// TrainedAt is when training finished, in UTC.
+	TrainedAt time.Time `json:"trained_at"`
+	// RowsUsed counts fetched rows with both FE and BE pods.
+	RowsUsed int `json:"rows_used"`
+	// RowsSkipped counts fetched rows without both pods, which are not trained on.
+	RowsSkipped int `json:"rows_skipped"`
+	// RowsRejected counts source rows dropped during ingestion.
+	RowsRejected int `json:"rows_rejected"`
+	// Reconciled counts stored predictions matched with newly arrived actuals.
+	Reconciled int `json:"reconciled"`
+	// TrainingDurationMS is the wall time spent in Model.Train.
+	TrainingDurationMS int64 `json:"training_duration_ms"`
+	// Model holds the new coefficients and R² when the Model implements model.Describer.
+	Model *model.Description `json:"model,omitempty"`
+}
+
+// trained is a model together with the fetch it was trained on and its
+// lineage. Retrain swaps it as a whole, so a prediction always records the
+// lineage of the model that made it.
+type trained struct {
+	model   model.Model
+	report  fetcher.Report  // ingest report of the fetch used for training
+	data    []metrics.Daily // rows the model was trained on
+	lineage store.Lineage   // its version changes on every training
+}
+
+// Retrain refetches the training data and trains a fresh model from the
+// factory set by WithModelFactory; without one, the Model passed to New is
+// retrained in place. Before training, stored predictions for days that now
+// have actual pods are reconciled (see accuracy.Reconcile).
+// It refuses to train when the ingest report exceeds the reject threshold,
+// and returns ErrRetrainInProgress instead of waiting if another retrain is
+// running. In-flight predictions keep using the previous model until the
+// new one is swapped in together with its lineage.
+func (h *Handler) Retrain(ctx context.Context) (RetrainSummary, error) {
+	if !h.retrainMu.TryLock() {
+		return RetrainSummary{}, ErrRetrainInProgress
+	}
+	defer h.retrainMu.Unlock()
+
+	data, rep, err := h.fetcher.Fetch(ctx)
+	if err != nil {
+		return RetrainSummary{}, err
+	}
+	if err := rep.CheckRejectionRate(h.maxRejectRatio); err != nil {
+		return RetrainSummary{}, err
+	}
+	reconciled, err := accuracy.Reconcile(ctx, h.store, data, reconcileSources, time.Now())
+	if err != nil {
+		return RetrainSummary{}, fmt.Errorf("reconciling predictions: %w", err)
+	}
+
+	m := h.model
+	if h.newModel != nil && h.current.Load() != nil {
+		m = h.newModel()
+	}
+	start := time.Now()
+	if err := m.Train(data); err != nil {
+		return RetrainSummary{}, err
+	}
+
+	sum := RetrainSummary{
+		ModelVersion:       uuid.New().String(),
+		DataFingerprint:    metrics.Fingerprint(data),
+		TrainedAt:          time.Now().UTC(),
+		RowsRejected:       len(rep.Rejected),
+		Reconciled:         reconciled,
+		TrainingDurationMS: time.Since(start).Milliseconds(),
+	}
+	for _, d := range data {
+		if d.HasPods() {
+			sum.RowsUsed++
+		} else {
+			sum.RowsSkipped++
+		}
+	}
+	if d, ok := m.(model.Describer); ok {
+		if desc, err := d.Describe(); err == nil {
+			sum.Model = &desc
+		}
+	}
+
+	lineage := store.Lineage{
+		ModelVersion:    sum.ModelVersion,
+		DataFingerprint: sum.DataFingerprint,
+		TrainedAt:       sum.TrainedAt,
+	}
+	if sum.Model != nil {
+		lineage.ModelType = sum.Model.Type
+	}
+
+	h.current.Store(&trained{model: m, report: rep, data: data, lineage: lineage})
+	return sum, nil
+}
+
+// POST /admin/retrain
+// Requires: Authorization: Bearer <admin token>
+// Returns: RetrainSummary, or 409 if a retrain is already running
+func (h *Handler) AdminRetrain(w http.ResponseWriter, r *http.Request) {
+	if r.Method != http.MethodPost {
+		writeError(w, http.StatusMethodNotAllowed, "method not allowed")
+		return
+	}
+
+	sum, err := h.Retrain(r.Context())
+	switch {
+	case errors.Is(err, ErrRetrainInProgress):
+		writeError(w, http.StatusConflict, err.Error())
+	case err != nil:
+		writeError(w, http.StatusInternalServerError, "retrain failed: "+err.Error())
+	default:
+		writeJSON(w, http.StatusOK, sum)
+	}
+}
+
+// requireAdmin rejects requests that do not carry the admin bearer token.
+func (h *Handler) requireAdmin(next http.HandlerFunc) http.HandlerFunc {
+	return func(w http.ResponseWriter, r *http.Request) {
+		token, ok := strings.CutPrefix(r.Header.Get("Authorization"), "Bearer ")
+		if !ok || subtle.ConstantTimeCompare([]byte(token), []byte(h.adminToken)) != 1 {
+			w.Header().Set("WWW-Authenticate", `Bearer realm="admin"`)
+			writeError(w, http.StatusUnauthorized, "unauthorized")
+			return
+		}
+		next(w, r)
+	}
+}
+
+// adminIfConfigured guards next with requireAdmin when an admin token is
+// set, for routes that are always available but destructive.
+func (h *Handler) adminIfConfigured(next http.HandlerFunc) http.HandlerFunc {
+	if h.adminToken == "" {
+		return next
+	}
+	return h.requireAdmin(next)
+}
+
+// Lineage returns the lineage of the current model, which is recorded on
+// every prediction it makes.
+func (h *Handler) Lineage() store.Lineage {
+	return h.current.Load().lineage
+}
+
+// Training is the model serving predictions together with the fetch it was
+// trained on and its lineage.
+type Training struct {
+	Model   model.Model
+	Data    []metrics.Daily // every fetched row, with or without pods
+	Report  fetcher.Report
+	Lineage store.Lineage
+}
+
+// Training returns the current model and its training fetch, all from the
+// same Retrain.
+func (h *Handler) Training() Training {
+	cur := h.current.Load()
+	return Training{Model: cur.model, Data: cur.data, Report: cur.report, Lineage: cur.lineage}
+}
@@ -0,0 +1,185 @@
+package api
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"net/http"
+	"time"
+
+	"github.com/google/uuid"
+	"github.com/thisiscetin/podpredict/internal/model"
+	"github.com/thisiscetin/podpredict/internal/store"
+)
+
+// DefaultMaxBatchSize is the default item limit of POST /predict/batch.
+const DefaultMaxBatchSize = 500
+
+// POST /predict/batch[?confidence=p90[&bound=upper]]
+// Body: [ { "id": <string, optional>, "date": <string, optional>, "gmv": <float>, "users": <float>, "marketing_cost": <float> }, ... ]
+// Returns: batchResponse with one result per item, in order, holding either
+// the stored prediction or the reason the item failed.
+// Client IDs become prediction IDs and must be unused; date becomes the
+// prediction timestamp (RFC 3339 or YYYY-MM-DD, default now), and the day a
+// model.Forecaster forecasts. Successful items are stored together, or not
+// at all if storing fails. Items outside the training range get warnings,
+// or fail under OODReject. Dates the store cannot order (see
+// store.ValidTimestamp) fail the whole batch with 422.
+func (h *Handler) PredictBatch(w http.ResponseWriter, r *http.Request) {
+	if r.Method != http.MethodPost {
+		writeError(w, http.StatusMethodNotAllowed, "method not allowed")
+		return
+	}
+	ctx, cancel := context.WithTimeout(r.Context(), h.timeout)
+	defer cancel()
+
+	confidence, bound, err := parseInterval(r)
+	if err != nil {
+		writeError(w, http.StatusBadRequest, err.Error())
+		return
+	}
+
+	var items []batchItem
+	if !h.decodeBody(w, r, &items) {
+		return
+	}
+	switch {
+	case len(items) == 0:
+		writeError(w, http.StatusBadRequest, "empty batch")
+		return
+	case len(items) > h.maxBatchSize:
+		writeError(w, http.StatusBadRequest, fmt.Sprintf("batch of %d items exceeds the limit of %d", len(items), h.maxBatchSize))
+		return
+	}
+	if invalid := unstorableDates(items); invalid != nil {
+		writeProblem(w, problem{
+			Status:        http.StatusUnprocessableEntity,
+			Detail:        "dates outside the supported range",
+			InvalidParams: invalid,
+		})
+		return
+	}
+
+	cur := h.current.Load()
+	now := time.Now().UTC()
+
+	resp := batchResponse{Results: make([]batchResult, len(items))}
+	recs := make([]store.Prediction, 0, len(items))
+	seen := make(map[string]struct{}, len(items))
+	for i, it := range items {
+		res := &resp.Results[i]
+		res.Index, res.ID = i, it.ID
+
+		in, invalid := it.features()
+		if invalid != nil {
+			res.Error, res.InvalidParams = "invalid features", invalid
+			resp.Failed++
+			continue
+		}
+		oor := h.outOfRange(cur.model, &in)
+		if oor != nil && h.oodPolicy == OODReject {
+			res.Error, res.InvalidParams = errOutOfRange, rangeParams(oor)
+			resp.Failed++
+			continue
+		}
+
+		rec, problem, err := h.prepareBatchItem(ctx, it, in, now, seen)
+		if err != nil {
+			writeError(w, http.StatusInternalServerError, "looking up prediction ids failed: "+err.Error())
+			return
+		}
+		if problem != "" {
+			res.Error = problem
+			resp.Failed++
+			continue
+		}
+
+		// Forecasting models predict dated items for their date.
+		var date time.Time
+		if it.Date != "" {
+			date = rec.Timestamp
+		}
+		fe, be, _, iv, err := cur.score(date, &in, false, confidence, bound)
+		if errors.Is(err, errNoIntervals) {
+			writeError(w, http.StatusNotImplemented, err.Error())
+			return
+		}
+		if err != nil {
+			res.Error = err.Error()
+			resp.Failed++
+			continue
+		}
+		rec.FEPods, rec.BEPods, rec.Interval = int(fe), int(be), iv
+		rec.Lineage = &cur.lineage
+		recs = append(recs, rec)
+		res.Prediction, res.Warnings = &rec, rangeWarnings(oor)
+		resp.Succeeded++
+	}
+
+	err = h.store.AppendBatch(ctx, recs)
+	switch {
+	case errors.Is(err, store.ErrExists):
+		writeError(w, http.StatusConflict, "a prediction id was stored concurrently; nothing was stored")
+		return
+	case err != nil:
+		writeError(w, http.StatusInternalServerError, "persisting predictions failed: "+err.Error())
+		return
+	}
+	writeJSON(w, http.StatusOK, resp)
+}
+
+// unstorableDates lists the item dates the store cannot order, which fail
+// the whole batch. Malformed dates fail only their item, in prepareBatchItem.
+func unstorableDates(items []batchItem) []invalidParam {
+	var out []invalidParam
+	for i, it := range items {
+		if it.Date == "" {
+			continue
+		}
+		if t, err := parseTime(it.Date); err == nil && !store.ValidTimestamp(t) {
+			out = append(out, invalidParam{
+				Name: fmt.Sprintf("[%d].date", i),
+				Reason: fmt.Sprintf("must be between %s and %s",
+					store.MinTimestamp.Format(time.RFC3339), store.MaxTimestamp.Format(time.RFC3339)),
+			})
+		}
+	}
+	return out
+}
+
+// prepareBatchItem validates the ID and date of it and returns the
+// prediction record of in to fill in, recording its ID in seen. A non-empty
+// problem fails only this item; err fails the whole batch.
+func (h *Handler) prepareBatchItem(ctx context.Context, it batchItem, in model.Features, now time.Time, seen map[string]struct{}) (rec store.Prediction, problem string, err error) {
+	rec = store.Prediction{
+		ID:        it.ID,
+		Timestamp: now,
+		Input:     in,
+		Source:    store.SourceAPI,
+		Kind:      store.KindPredicted,
+	}
+	if it.Date != "" {
+		t, err := parseTime(it.Date)
+		if err != nil {
+			return store.Prediction{}, "invalid date: want RFC 3339 or YYYY-MM-DD", nil
+		}
+		rec.Timestamp = t
+	}
+
+	if rec.ID == "" {
+		rec.ID = uuid.New().String()
+		return rec, "", nil
+	}
+	if _, ok := seen[rec.ID]; ok {
+		return store.Prediction{}, "duplicate id in batch", nil
+	}
+	_, err = h.store.Get(ctx, rec.ID)
+	switch {
+	case err == nil:
+		return store.Prediction{}, "id already exists", nil
+	case !errors.Is(err, store.ErrNotFound):
+		return store.Prediction{}, "", err
+	}
+	seen[rec.ID] = struct{}{}
+	return rec, "", nil
+}
@@ -0,0 +1,98 @@
+package api
+
+import (
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"net/http"
+	"strings"
+
+	"github.com/thisiscetin/podpredict/internal/model"
+)
+
+// DefaultMaxBodyBytes is the default size limit of request bodies.
+const DefaultMaxBodyBytes = 1 << 20
+
+// decodeBody strictly decodes the JSON body of r into dst: unknown fields,
+// trailing data and bodies larger than the handler's limit are rejected.
+// On failure it writes a problem response and returns false.
+func (h *Handler) decodeBody(w http.ResponseWriter, r *http.Request, dst any) bool {
+	dec := json.NewDecoder(http.MaxBytesReader(w, r.Body, h.maxBodyBytes))
+	dec.DisallowUnknownFields()
+
+	err := dec.Decode(dst)
+	if err == nil && dec.Decode(&struct{}{}) != io.EOF {
+		err = errors.New("unexpected data after the JSON value")
+	}
+	if err == nil {
+		return true
+	}
+
+	var (
+		tooLarge *http.MaxBytesError
+		typeErr  *json.UnmarshalTypeError
+	)
+	switch {
+	case errors.As(err, &tooLarge):
+		writeProblem(w, problem{
+			Status: http.StatusRequestEntityTooLarge,
+			Detail: fmt.Sprintf("request body exceeds %d bytes", tooLarge.Limit),
+		})
+	case errors.As(err, &typeErr):
+		writeInvalid(w, []invalidParam{{Name: typeErr.Field, Reason: fmt.Sprintf("got JSON %s, want %s", typeErr.Value, typeErr.Type)}})
+	case strings.HasPrefix(err.Error(), "json: unknown field "):
+		name := strings.Trim(strings.TrimPrefix(err.Error(), "json: unknown field "), `"`)
+		writeInvalid(w, []invalidParam{{Name: name, Reason: "unknown field"}})
+	default:
+		writeError(w, http.StatusBadRequest, "invalid json body: "+err.Error())
+	}
+	return false
+}
+
+// writeInvalid rejects a request whose body has invalid fields.
+func writeInvalid(w http.ResponseWriter, params []invalidParam) {
+	writeProblem(w, problem{
+		Status:        http.StatusBadRequest,
+		Detail:        "request body has invalid fields",
+		InvalidParams: params,
+	})
+}
+
+// featuresInput is the JSON form of model.Features in request bodies,
+// with pointers so that missing fields can be told apart from zeros.
+type featuresInput struct {
+	GMV           *float64 `json:"gmv"`
+	Users         *float64 `json:"users"`
+	MarketingCost *float64 `json:"marketing_cost"`
+}
+
+// features returns in as model.Features, or the fields that are missing
+// or break the rules of model.Features.Validate.
+func (in featuresInput) features() (model.Features, []invalidParam) {
+	var params []invalidParam
+	for _, f := range []struct {
+		name string
+		v    *float64
+	}{
+		{"gmv", in.GMV},
+		{"users", in.Users},
+		{"marketing_cost", in.MarketingCost},
+	} {
+		if f.v == nil {
+			params = append(params, invalidParam{Name: f.name, Reason: "is required"})
+		}
+	}
+	if params != nil {
+		return model.Features{}, params
+	}
+
+	f := model.Features{GMV: *in.GMV, Users: *in.Users, MarketingCost: *in.MarketingCost}
+	for _, fe := range f.Validate() {
+		params = append(params, invalidParam{Name: fe.Field, Reason: fe.Error()})
+	}
+	if params != nil {
+		return model.Features{}, params
+	}
+	return f, nil
+}
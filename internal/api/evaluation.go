@@ -0,0 +1,81 @@
+package api
+
+import (
+	"fmt"
+	"net/http"
+	"strconv"
+	"sync"
+
+	"github.com/thisiscetin/podpredict/internal/eval"
+)
+
+// evaluationResponse is the body of GET /model/evaluation.
+type evaluationResponse struct {
+	ModelVersion string `json:"model_version"`
+	eval.Result
+}
+
+// evaluationCache holds the last evaluation of GET /model/evaluation.
+type evaluationCache struct {
+	// mu serializes evaluations, so concurrent requests retrain one at a time.
+	mu   sync.Mutex
+	opts eval.Options
+	resp *evaluationResponse
+}
+
+// GET /model/evaluation[?k=5][&min_train=N][&horizon=1]
+// Requires the admin bearer token when one is configured.
+// Returns: k-fold and walk-forward backtest metrics (MAE, RMSE, MAPE,
+// under/over-provisioned %) for FE and BE on the current training data.
+// Evaluations retrain the model many times, so they run one at a time and
+// the last result is served again until the model or the parameters change.
+func (h *Handler) EvaluateModel(w http.ResponseWriter, r *http.Request) {
+	if r.Method != http.MethodGet {
+		writeError(w, http.StatusMethodNotAllowed, "method not allowed")
+		return
+	}
+	if h.newModel == nil {
+		writeError(w, http.StatusNotImplemented, "model evaluation is not configured")
+		return
+	}
+
+	var opts eval.Options
+	q := r.URL.Query()
+	for _, p := range []struct {
+		name string
+		dst  *int
+	}{
+		{"k", &opts.Folds},
+		{"min_train", &opts.MinTrain},
+		{"horizon", &opts.Horizon},
+	} {
+		v := q.Get(p.name)
+		if v == "" {
+			continue
+		}
+		n, err := strconv.Atoi(v)
+		if err != nil || n < 1 {
+			writeError(w, http.StatusBadRequest, fmt.Sprintf("invalid %s parameter", p.name))
+			return
+		}
+		*p.dst = n
+	}
+
+	h.eval.mu.Lock()
+	defer h.eval.mu.Unlock()
+
+	cur := h.current.Load()
+	data, version := cur.data, cur.lineage.ModelVersion
+
+	if c := h.eval.resp; c != nil && c.ModelVersion == version && h.eval.opts == opts {
+		writeJSON(w, http.StatusOK, c)
+		return
+	}
+	res, err := eval.Run(h.newModel, data, opts)
+	if err != nil {
+		writeError(w, http.StatusUnprocessableEntity, "evaluation failed: "+err.Error())
+		return
+	}
+	h.eval.opts, h.eval.resp = opts, &evaluationResponse{ModelVersion: version, Result: res}
+	writeJSON(w, http.StatusOK, h.eval.resp)
+}
@@ -0,0 +1,129 @@
+package api
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"net/http"
+	"time"
+
+	"github.com/thisiscetin/podpredict/internal/forecast"
+	"github.com/thisiscetin/podpredict/internal/metrics"
+	"github.com/thisiscetin/podpredict/internal/model"
+	"github.com/thisiscetin/podpredict/internal/store"
+)
+
+// MaxForecastDays bounds the number of days of one GET /forecast.
+const MaxForecastDays = 366
+
+// GET /forecast?from=YYYY-MM-DD&to=YYYY-MM-DD
+// Returns: one store.Prediction per UTC day from from to to, both
+// inclusive, under days. A model.Forecaster forecasts every day from the
+// date alone (kpis: none). Other models predict each day from its KPIs in
+// the training data when present (kpis: sheet), or from KPIs extrapolated
+// from the recent trend and weekly pattern (kpis: extrapolated).
+// Days after the training data are stored in one batch with source
+// forecast, the target date as timestamp and the ID of that day's natural
+// key, so retraining reconciles them with the actual pods. Only the first
+// forecast of a day is stored (stored: true), so GET /accuracy measures
+// forecasts made ahead of time; later ones are returned but not stored.
+// Days within the training data are in-sample: they are returned with
+// in_sample: true and no ID, and never stored.
+// Inputs outside the training range are listed under warnings of each day
+// under OODWarn; OODReject does not apply to forecasts.
+func (h *Handler) Forecast(w http.ResponseWriter, r *http.Request) {
+	if r.Method != http.MethodGet {
+		writeError(w, http.StatusMethodNotAllowed, "method not allowed")
+		return
+	}
+	ctx, cancel := context.WithTimeout(r.Context(), h.timeout)
+	defer cancel()
+
+	from, to, err := parseRange(r)
+	if err != nil {
+		writeError(w, http.StatusBadRequest, err.Error())
+		return
+	}
+	switch {
+	case from.IsZero() || to.IsZero():
+		writeError(w, http.StatusBadRequest, "from and to are required")
+		return
+	case to.Before(from):
+		writeError(w, http.StatusBadRequest, "to must not be before from")
+		return
+	case to.Sub(from).Hours()/24 >= MaxForecastDays:
+		writeError(w, http.StatusBadRequest, fmt.Sprintf("at most %d days can be forecast at once", MaxForecastDays))
+		return
+	}
+
+	cur := h.current.Load()
+	days, err := forecast.Run(cur.model, cur.data, from, to)
+	if errors.Is(err, forecast.ErrNoHistory) || errors.Is(err, model.ErrBeforeHistory) {
+		writeError(w, http.StatusUnprocessableEntity, "forecast failed: "+err.Error())
+		return
+	}
+	if err != nil {
+		writeError(w, http.StatusInternalServerError, "forecast failed: "+err.Error())
+		return
+	}
+
+	last := lastTrainingDay(cur.data)
+	resp := forecastResponse{Days: make([]forecastDay, len(days))}
+	var recs []store.Prediction
+	for i, d := range days {
+		fd := &resp.Days[i]
+		fd.Prediction = store.Prediction{
+			Timestamp: d.Date,
+			Input:     d.Input,
+			FEPods:    int(d.FEPods),
+			BEPods:    int(d.BEPods),
+			Source:    store.SourceForecast,
+			Kind:      store.KindPredicted,
+			Lineage:   &cur.lineage,
+		}
+		fd.KPIs = d.KPIs
+		if d.KPIs != forecast.KPIsNone {
+			fd.Warnings = rangeWarnings(h.outOfRange(cur.model, &d.Input))
+		}
+		if !d.Date.After(last) {
+			fd.InSample = true
+			continue
+		}
+
+		fd.Prediction.ID = store.KeyOf(fd.Prediction).ID()
+		_, err := h.store.Get(ctx, fd.ID)
+		switch {
+		case errors.Is(err, store.ErrNotFound):
+			fd.Stored = true
+			recs = append(recs, fd.Prediction)
+		case err != nil:
+			writeError(w, http.StatusInternalServerError, "looking up forecasts failed: "+err.Error())
+			return
+		}
+	}
+
+	if len(recs) > 0 {
+		err = h.store.AppendBatch(ctx, recs)
+		switch {
+		case errors.Is(err, store.ErrExists):
+			writeError(w, http.StatusConflict, "a day was forecast concurrently; nothing was stored")
+			return
+		case err != nil:
+			writeError(w, http.StatusInternalServerError, "persisting forecast failed: "+err.Error())
+			return
+		}
+	}
+	writeJSON(w, http.StatusOK, resp)
+}
+
+// lastTrainingDay returns the date of the last row with pods in data, or
+// the zero time if there is none.
+func lastTrainingDay(data []metrics.Daily) time.Time {
+	var last time.Time
+	for _, d := range data {
+		if d.HasPods() && d.Date.After(last) {
+			last = d.Date
+		}
+	}
+	return last.UTC().Truncate(24 * time.Hour)
+}
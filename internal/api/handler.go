@@ -2,9 +2,12 @@ package api
 
 import (
 	"context"
-	"encoding/json"
 	"errors"
+	"fmt"
 	"net/http"
+	"strconv"
+	"sync"
+	"sync/atomic"
 	"time"
 
 	"github.com/google/uuid"
@@ -15,16 +18,38 @@ import (
 
 // Handler owns HTTP endpoints and their dependencies.
 type Handler struct {
-	model   model.Model
+	model   model.Model // trained first; retrained in place without a factory
 	fetcher fetcher.Fetcher
 	store   store.Store
 
 	// optional: request-scoped timeout
 	timeout time.Duration
+	// maxRejectRatio bounds the fraction of rejected source rows accepted for training.
+	maxRejectRatio float64
+
+	// adminToken guards /admin routes; they are not registered when empty.
+	adminToken string
+	// oodPolicy treats inputs outside the training range.
+	oodPolicy OODPolicy
+	// maxBodyBytes limits the size of request bodies.
+	maxBodyBytes int64
+	// maxBatchSize limits the items of POST /predict/batch.
+	maxBatchSize int
+	// newModel builds fresh models for GET /model/evaluation; nil disables it.
+	newModel model.Factory
+	// eval caches the result of GET /model/evaluation.
+	eval evaluationCache
+
+	// retrainMu serializes retraining; Predict is never blocked by it.
+	retrainMu sync.Mutex
+
+	// current is the model serving predictions, swapped as a whole on Retrain.
+	current atomic.Pointer[trained]
 }
 
 // New wires dependencies, fetches training data via Fetcher, and trains the Model.
-func New(m model.Model, f fetcher.Fetcher, st store.Store, timeout time.Duration) (*Handler, error) {
+// ctx bounds the initial fetch.
+func New(ctx context.Context, m model.Model, f fetcher.Fetcher, st store.Store, timeout time.Duration, opts ...Option) (*Handler, error) {
 	if m == nil {
 		return nil, errors.New("nil model")
 	}
@@ -35,29 +60,39 @@ func New(m model.Model, f fetcher.Fetcher, st store.Store, timeout time.Duration
 		return nil, errors.New("nil store")
 	}
 
-	// Initial training
-	data, err := f.Fetch()
-	if err != nil {
-		return nil, err
+	if timeout <= 0 {
+		timeout = 5 * time.Second
 	}
-	if err := m.Train(data); err != nil {
-		return nil, err
+	h := &Handler{
+		model:          m,
+		fetcher:        f,
+		store:          st,
+		timeout:        timeout,
+		maxRejectRatio: 1,
+		oodPolicy:      OODWarn,
+		maxBodyBytes:   DefaultMaxBodyBytes,
+		maxBatchSize:   DefaultMaxBatchSize,
+	}
+	for _, opt := range opts {
+		opt(h)
 	}
 
-	if timeout <= 0 {
-		timeout = 5 * time.Second
+	// Initial training
+	if _, err := h.Retrain(ctx); err != nil {
+		return nil, err
 	}
-	return &Handler{
-		model:   m,
-		fetcher: f,
-		store:   st,
-		timeout: timeout,
-	}, nil
+	return h, nil
 }
 
-// POST /predict
+// POST /predict[?explain=true][&confidence=p90[&bound=upper]]
 // Body: { "gmv": <float>, "users": <float>, "marketing_cost": <float> }
-// Returns: store.Prediction (with timestamp)
+// Returns: store.Prediction (with timestamp), plus a per-tier explanation
+// of raw values and feature contributions when explain=true.
+// confidence adds prediction intervals; bound (point, lower or upper,
+// default point) picks which value becomes the recommended pod count.
+// Inputs outside the training range are listed under warnings, or
+// rejected with 422, depending on the OODPolicy. Models that forecast by
+// date (model.Forecaster) reject it with 422.
 func (h *Handler) Predict(w http.ResponseWriter, r *http.Request) {
 	if r.Method != http.MethodPost {
 		writeError(w, http.StatusMethodNotAllowed, "method not allowed")
@@ -66,15 +101,50 @@ func (h *Handler) Predict(w http.ResponseWriter, r *http.Request) {
 	ctx, cancel := context.WithTimeout(r.Context(), h.timeout)
 	defer cancel()
 
-	var in model.Features
-	if err := json.NewDecoder(r.Body).Decode(&in); err != nil {
-		writeError(w, http.StatusBadRequest, "invalid json body")
+	explain := false
+	if v := r.URL.Query().Get("explain"); v != "" {
+		b, err := strconv.ParseBool(v)
+		if err != nil {
+			writeError(w, http.StatusBadRequest, "invalid explain parameter")
+			return
+		}
+		explain = b
+	}
+	confidence, bound, err := parseInterval(r)
+	if err != nil {
+		writeError(w, http.StatusBadRequest, err.Error())
 		return
 	}
 
-	fe, be, err := h.model.Predict(&in)
-	if err != nil {
-		writeError(w, http.StatusInternalServerError, "prediction failed: "+err.Error())
+	var body featuresInput
+	if !h.decodeBody(w, r, &body) {
+		return
+	}
+	in, invalid := body.features()
+	if invalid != nil {
+		writeInvalid(w, invalid)
+		return
+	}
+	// Range check, prediction and lineage all come from the same training,
+	// even if a retrain swaps the model concurrently.
+	cur := h.current.Load()
+	oor := h.outOfRange(cur.model, &in)
+	if oor != nil && h.oodPolicy == OODReject {
+		writeOutOfRange(w, oor)
+		return
+	}
+
+	fe, be, ex, iv, err := cur.score(time.Time{}, &in, explain, confidence, bound)
+	switch {
+	case errors.Is(err, errNotExplainable), errors.Is(err, errNoIntervals):
+		writeError(w, http.StatusNotImplemented, err.Error())
+		return
+	case errors.Is(err, model.ErrNeedsDate):
+		writeError(w, http.StatusUnprocessableEntity,
+			err.Error()+": use POST /predict/batch with a date or GET /forecast")
+		return
+	case err != nil:
+		writeError(w, http.StatusInternalServerError, err.Error())
 		return
 	}
 
@@ -84,16 +154,124 @@ func (h *Handler) Predict(w http.ResponseWriter, r *http.Request) {
 		Input:     in,
 		FEPods:    int(fe),
 		BEPods:    int(be),
+		Source:    store.SourceAPI,
+		Kind:      store.KindPredicted,
+		Lineage:   &cur.lineage,
+		Interval:  iv,
 	}
 	if err := h.store.Append(ctx, rec); err != nil {
 		writeError(w, http.StatusInternalServerError, "persisting prediction failed: "+err.Error())
 		return
 	}
-	writeJSON(w, http.StatusCreated, rec)
+	writeJSON(w, http.StatusCreated, predictResponse{Prediction: rec, Explanation: ex, Warnings: rangeWarnings(oor)})
 }
 
-// GET /predictions
-// Returns: []store.Prediction
+// score predicts pods for in on date (see model.PredictOn; zero for no
+// particular day), with an explanation when explain is set and prediction
+// intervals when confidence is positive, in which case bound picks the
+// recommended pod counts.
+func (t *trained) score(date time.Time, in *model.Features, explain bool, confidence float64, bound model.Bound) (
+	fe model.FEPods, be model.BEPods, ex *model.Explanation, iv *model.Interval, err error,
+) {
+	if explain {
+		ex, err = t.explain(in)
+		if errors.Is(err, errNotExplainable) {
+			return 0, 0, nil, nil, err
+		}
+		if ex != nil {
+			fe, be = model.FEPods(ex.FE.Pods), model.BEPods(ex.BE.Pods)
+		}
+	} else {
+		fe, be, err = model.PredictOn(t.model, date, in)
+	}
+	if err != nil {
+		return 0, 0, nil, nil, fmt.Errorf("prediction failed: %w", err)
+	}
+
+	if confidence > 0 {
+		iv, err = t.interval(in, confidence)
+		if errors.Is(err, errNoIntervals) {
+			return 0, 0, nil, nil, err
+		}
+		if err != nil {
+			return 0, 0, nil, nil, fmt.Errorf("prediction interval failed: %w", err)
+		}
+		iv.Bound = bound
+		if bound != model.BoundPoint {
+			fe, be = model.FEPods(iv.FE.Pods(bound)), model.BEPods(iv.BE.Pods(bound))
+		}
+	}
+	return fe, be, ex, iv, nil
+}
+
+// parseInterval reads the confidence and bound query parameters.
+// A zero confidence means no interval was requested.
+func parseInterval(r *http.Request) (float64, model.Bound, error) {
+	q := r.URL.Query()
+	bound := model.BoundPoint
+	if v := q.Get("bound"); v != "" {
+		b, err := model.ParseBound(v)
+		if err != nil {
+			return 0, "", err
+		}
+		bound = b
+	}
+	v := q.Get("confidence")
+	if v == "" {
+		if q.Has("bound") {
+			return 0, "", errors.New("bound requires a confidence parameter")
+		}
+		return 0, bound, nil
+	}
+	c, err := model.ParseConfidence(v)
+	if err != nil {
+		return 0, "", err
+	}
+	return c, bound, nil
+}
+
+// errNoIntervals is returned by interval when the model is not a model.IntervalPredictor.
+var errNoIntervals = errors.New("model does not support prediction intervals")
+
+// interval computes FE/BE prediction intervals at the given confidence.
+func (t *trained) interval(in *model.Features, confidence float64) (*model.Interval, error) {
+	p, ok := t.model.(model.IntervalPredictor)
+	if !ok {
+		return nil, errNoIntervals
+	}
+	iv, err := p.PredictInterval(in, confidence)
+	if err != nil {
+		return nil, err
+	}
+	return &iv, nil
+}
+
+// errNotExplainable is returned by explain when the model is not a model.Explainer.
+var errNotExplainable = errors.New("model does not support explanations")
+
+// explain predicts through model.Explainer so that pods and explanation
+// come from the same model state.
+func (t *trained) explain(in *model.Features) (*model.Explanation, error) {
+	e, ok := t.model.(model.Explainer)
+	if !ok {
+		return nil, errNotExplainable
+	}
+	ex, err := e.Explain(in)
+	if err != nil {
+		return nil, err
+	}
+	return &ex, nil
+}
+
+// Page sizes of GET /predictions.
+const (
+	DefaultPageSize = 100
+	MaxPageSize     = 1000
+)
+
+// GET /predictions[?from=&to=][&kind=actual|predicted][&reconciled=true][&model_version=][&min_fe_pods=&max_fe_pods=][&min_be_pods=&max_be_pods=][&order=asc|desc][&limit=100][&cursor=]
+// from/to accept RFC 3339 timestamps or YYYY-MM-DD dates (from inclusive, to exclusive).
+// Returns: store.Page; pass next_cursor as cursor to fetch the following page
 func (h *Handler) ListPredictions(w http.ResponseWriter, r *http.Request) {
 	if r.Method != http.MethodGet {
 		writeError(w, http.StatusMethodNotAllowed, "method not allowed")
@@ -102,12 +280,142 @@ func (h *Handler) ListPredictions(w http.ResponseWriter, r *http.Request) {
 	ctx, cancel := context.WithTimeout(r.Context(), h.timeout)
 	defer cancel()
 
-	items, err := h.store.List(ctx)
+	q, err := parseQuery(r)
+	if err != nil {
+		writeError(w, http.StatusBadRequest, err.Error())
+		return
+	}
+	page, err := h.store.List(ctx, q)
+	if errors.Is(err, store.ErrInvalidQuery) {
+		writeError(w, http.StatusBadRequest, err.Error())
+		return
+	}
 	if err != nil {
 		writeError(w, http.StatusInternalServerError, "listing predictions failed: "+err.Error())
 		return
 	}
-	writeJSON(w, http.StatusOK, items)
+	writeJSON(w, http.StatusOK, page)
+}
+
+// parseQuery builds a store.Query from the GET /predictions parameters.
+func parseQuery(r *http.Request) (store.Query, error) {
+	v := r.URL.Query()
+	q := store.Query{
+		Kind:         store.Kind(v.Get("kind")),
+		ModelVersion: v.Get("model_version"),
+		Order:        store.Order(v.Get("order")),
+		Cursor:       v.Get("cursor"),
+		Limit:        DefaultPageSize,
+	}
+	if s := v.Get("reconciled"); s != "" {
+		b, err := strconv.ParseBool(s)
+		if err != nil {
+			return store.Query{}, errors.New("invalid reconciled parameter")
+		}
+		q.Reconciled = b
+	}
+
+	var err error
+	if q.From, q.To, err = parseRange(r); err != nil {
+		return store.Query{}, err
+	}
+
+	for _, p := range []struct {
+		name string
+		dst  *int
+	}{
+		{"limit", &q.Limit},
+		{"min_fe_pods", &q.MinFEPods},
+		{"max_fe_pods", &q.MaxFEPods},
+		{"min_be_pods", &q.MinBEPods},
+		{"max_be_pods", &q.MaxBEPods},
+	} {
+		if s := v.Get(p.name); s != "" {
+			n, err := strconv.Atoi(s)
+			if err != nil || n < 0 {
+				return store.Query{}, fmt.Errorf("invalid %s parameter", p.name)
+			}
+			*p.dst = n
+		}
+	}
+	if q.Limit < 1 || q.Limit > MaxPageSize {
+		return store.Query{}, fmt.Errorf("limit must be between 1 and %d", MaxPageSize)
+	}
+	return q, nil
+}
+
+// parseRange reads the optional from and to query parameters.
+func parseRange(r *http.Request) (from, to time.Time, err error) {
+	v := r.URL.Query()
+	for _, p := range []struct {
+		name string
+		dst  *time.Time
+	}{
+		{"from", &from},
+		{"to", &to},
+	} {
+		if s := v.Get(p.name); s != "" {
+			t, err := parseTime(s)
+			if err != nil {
+				return time.Time{}, time.Time{}, fmt.Errorf("invalid %s parameter: want RFC 3339 or YYYY-MM-DD", p.name)
+			}
+			*p.dst = t
+		}
+	}
+	return from, to, nil
+}
+
+// parseTime accepts an RFC 3339 timestamp or a YYYY-MM-DD date (UTC midnight).
+func parseTime(s string) (time.Time, error) {
+	if t, err := time.Parse(time.RFC3339, s); err == nil {
+		return t.UTC(), nil
+	}
+	return time.Parse(time.DateOnly, s)
+}
+
+// GET /predictions/{id}
+// Returns: store.Prediction, or 404 if no prediction has that ID
+func (h *Handler) GetPrediction(w http.ResponseWriter, r *http.Request) {
+	if r.Method != http.MethodGet {
+		writeError(w, http.StatusMethodNotAllowed, "method not allowed")
+		return
+	}
+	ctx, cancel := context.WithTimeout(r.Context(), h.timeout)
+	defer cancel()
+
+	p, err := h.store.Get(ctx, r.PathValue("id"))
+	if errors.Is(err, store.ErrNotFound) {
+		writeError(w, http.StatusNotFound, err.Error())
+		return
+	}
+	if err != nil {
+		writeError(w, http.StatusInternalServerError, "getting prediction failed: "+err.Error())
+		return
+	}
+	writeJSON(w, http.StatusOK, p)
+}
+
+// DELETE /predictions/{id}
+// Requires the admin bearer token when one is configured.
+// Returns: 204, or 404 if no prediction has that ID
+func (h *Handler) DeletePrediction(w http.ResponseWriter, r *http.Request) {
+	if r.Method != http.MethodDelete {
+		writeError(w, http.StatusMethodNotAllowed, "method not allowed")
+		return
+	}
+	ctx, cancel := context.WithTimeout(r.Context(), h.timeout)
+	defer cancel()
+
+	err := h.store.Delete(ctx, r.PathValue("id"))
+	if errors.Is(err, store.ErrNotFound) {
+		writeError(w, http.StatusNotFound, err.Error())
+		return
+	}
+	if err != nil {
+		writeError(w, http.StatusInternalServerError, "deleting prediction failed: "+err.Error())
+		return
+	}
+	w.WriteHeader(http.StatusNoContent)
 }
 
 // GET /healthz
@@ -133,7 +441,7 @@ func (h *Handler) HealthCheck(w http.ResponseWriter, r *http.Request) {
 	}
 
 	// Check store health
-	if _, err := h.store.List(ctx); err != nil {
+	if _, err := h.store.List(ctx, store.Query{Limit: 1}); err != nil {
 		status.StoreOK = false
 		status.Status = "degraded"
 	} else {
@@ -143,11 +451,13 @@ func (h *Handler) HealthCheck(w http.ResponseWriter, r *http.Request) {
 	writeJSON(w, http.StatusOK, status)
 }
 
-// Optional: expose retraining for future endpoints/CLI
-func (h *Handler) Retrain(ctx context.Context) error {
-	data, err := h.fetcher.Fetch()
-	if err != nil {
-		return err
+// GET /ingest/report
+// Returns: fetcher.Report of the data the current model was trained on
+func (h *Handler) IngestReport(w http.ResponseWriter, r *http.Request) {
+	if r.Method != http.MethodGet {
+		writeError(w, http.StatusMethodNotAllowed, "method not allowed")
+		return
 	}
-	return h.model.Train(data)
+
+	writeJSON(w, http.StatusOK, h.current.Load().report)
 }
@@ -5,14 +5,18 @@ import (
 	"context"
 	"encoding/json"
 	"errors"
+	"fmt"
 	"net/http"
 	"net/http/httptest"
+	"strconv"
+	"strings"
 	"testing"
 	"time"
 
 	"github.com/stretchr/testify/assert"
 	"github.com/stretchr/testify/require"
 
+	"github.com/thisiscetin/podpredict/internal/fetcher"
 	"github.com/thisiscetin/podpredict/internal/metrics"
 	"github.com/thisiscetin/podpredict/internal/model"
 	"github.com/thisiscetin/podpredict/internal/store"
@@ -33,12 +37,62 @@ func (m *mockModel) Predict(_ *model.Features) (model.FEPods, model.BEPods, erro
 	return m.fe, m.be, m.err
 }
 
+// describingModel is a mockModel that also implements model.Describer.
+type describingModel struct {
+	mockModel
+	desc model.Description
+}
+
+func (m *describingModel) Describe() (model.Description, error) { return m.desc, nil }
+
+// explainingModel is a mockModel that also implements model.Explainer.
+type explainingModel struct {
+	mockModel
+	ex model.Explanation
+}
+
+func (m *explainingModel) Explain(_ *model.Features) (model.Explanation, error) { return m.ex, m.err }
+
+// intervalModel is a mockModel that also implements model.IntervalPredictor.
+type intervalModel struct {
+	mockModel
+}
+
+func (m *intervalModel) PredictInterval(_ *model.Features, c float64) (model.Interval, error) {
+	return model.Interval{
+		Confidence: c,
+		FE:         model.Bounds{Point: float64(m.fe), Lower: float64(m.fe) - 1.5, Upper: float64(m.fe) + 2.2},
+		BE:         model.Bounds{Point: float64(m.be), Lower: float64(m.be) - 0.5, Upper: float64(m.be) + 0.3},
+	}, nil
+}
+
+// profilingModel is a mockModel that also implements model.Profiler.
+type profilingModel struct {
+	mockModel
+	profile model.Profile
+}
+
+func (m *profilingModel) Profile() (model.Profile, error) { return m.profile, nil }
+
+// forecastingModel is a mockModel that also implements model.Forecaster,
+// forecasting the day of the month as FE pods.
+type forecastingModel struct {
+	mockModel
+}
+
+func (m *forecastingModel) Forecast(date time.Time) (model.FEPods, model.BEPods, error) {
+	return model.FEPods(date.Day()), m.be, nil
+}
+
 type mockFetcher struct {
 	out []metrics.Daily
+	rep fetcher.Report
 	err error
 }
 
-func (f mockFetcher) Fetch() ([]metrics.Daily, error) { return f.out, f.err }
+func (f mockFetcher) Fetch(_ context.Context) ([]metrics.Daily, fetcher.Report, error) {
+	return f.out, f.rep, f.err
+}
 
 type mockStore struct {
 	items []store.Prediction
@@ -53,13 +107,49 @@ func (s *mockStore) Append(_ context.Context, r store.Prediction) error {
 	s.items = append(s.items, r)
 	return nil
 }
-func (s *mockStore) List(_ context.Context) ([]store.Prediction, error) {
+func (s *mockStore) AppendBatch(_ context.Context, rs []store.Prediction) error {
+	if s.aerr != nil {
+		return s.aerr
+	}
+	s.items = append(s.items, rs...)
+	return nil
+}
+func (s *mockStore) Upsert(_ context.Context, r store.Prediction) error {
+	r, err := store.PrepareUpsert(r)
+	if err != nil {
+		return err
+	}
+	for i, it := range s.items {
+		if it.ID == r.ID {
+			s.items[i] = r
+			return nil
+		}
+	}
+	s.items = append(s.items, r)
+	return nil
+}
+func (s *mockStore) Get(_ context.Context, id string) (store.Prediction, error) {
+	for _, it := range s.items {
+		if it.ID == id {
+			return it, nil
+		}
+	}
+	return store.Prediction{}, store.ErrNotFound
+}
+func (s *mockStore) Delete(_ context.Context, id string) error {
+	for i, it := range s.items {
+		if it.ID == id {
+			s.items = append(s.items[:i], s.items[i+1:]...)
+			return nil
+		}
+	}
+	return store.ErrNotFound
+}
+func (s *mockStore) List(_ context.Context, q store.Query) (store.Page, error) {
 	if s.lerr != nil {
-		return nil, s.lerr
+		return store.Page{}, s.lerr
 	}
-	out := make([]store.Prediction, len(s.items))
-	copy(out, s.items)
-	return out, nil
+	return store.Apply(s.items, q)
 }
 
 func TestNew_TrainsModel(t *testing.T) {
@@ -67,7 +157,7 @@ func TestNew_TrainsModel(t *testing.T) {
 	ff := mockFetcher{out: []metrics.Daily{}}
 	ss := &mockStore{}
 
-	h, err := New(mm, ff, ss, 2*time.Second)
+	h, err := New(context.Background(), mm, ff, ss, 2*time.Second)
 	require.NoError(t, err)
 	require.NotNil(t, h)
 	assert.NotNil(t, mm.trainedWith)
@@ -78,7 +168,7 @@ func TestPredict_Success(t *testing.T) {
 	ff := mockFetcher{out: []metrics.Daily{}}
 	ss := &mockStore{}
 
-	h, err := New(mm, ff, ss, time.Second)
+	h, err := New(context.Background(), mm, ff, ss, time.Second)
 	require.NoError(t, err)
 
 	srv := httptest.NewServer(Routes(h))
@@ -98,11 +188,13 @@ func TestPredict_Success(t *testing.T) {
 	assert.Equal(t, 1000.0, got.Input.GMV)
 	assert.Equal(t, 50.0, got.Input.Users)
 	assert.Equal(t, 12.0, got.Input.MarketingCost)
+	assert.Equal(t, store.SourceAPI, got.Source)
+	assert.Equal(t, store.KindPredicted, got.Kind)
 
 	// Ensure it was stored
-	items, err := ss.List(context.Background())
+	page, err := ss.List(context.Background(), store.Query{})
 	require.NoError(t, err)
-	require.Len(t, items, 1)
+	require.Len(t, page.Items, 1)
 }
 
 func TestPredict_BadJSON(t *testing.T) {
@@ -110,7 +202,7 @@ func TestPredict_BadJSON(t *testing.T) {
 	ff := mockFetcher{out: []metrics.Daily{}}
 	ss := &mockStore{}
 
-	h, _ := New(mm, ff, ss, time.Second)
+	h, _ := New(context.Background(), mm, ff, ss, time.Second)
 	srv := httptest.NewServer(Routes(h))
 	defer srv.Close()
 
@@ -125,7 +217,7 @@ func TestPredict_ModelError(t *testing.T) {
 	ff := mockFetcher{out: []metrics.Daily{}}
 	ss := &mockStore{}
 
-	h, _ := New(mm, ff, ss, time.Second)
+	h, _ := New(context.Background(), mm, ff, ss, time.Second)
 	srv := httptest.NewServer(Routes(h))
 	defer srv.Close()
 
@@ -136,12 +228,29 @@ func TestPredict_ModelError(t *testing.T) {
 	assert.Equal(t, http.StatusInternalServerError, resp.StatusCode)
 }
 
+func TestPredict_NeedsDate(t *testing.T) {
+	mm := &mockModel{err: fmt.Errorf("wrapped: %w", model.ErrNeedsDate)}
+	ff := mockFetcher{out: []metrics.Daily{}}
+	ss := &mockStore{}
+
+	h, _ := New(context.Background(), mm, ff, ss, time.Second)
+	srv := httptest.NewServer(Routes(h))
+	defer srv.Close()
+
+	resp, err := http.Post(srv.URL+"/predict", "application/json",
+		bytes.NewReader([]byte(`{"gmv":1,"users":1,"marketing_cost":1}`)))
+	require.NoError(t, err)
+	defer resp.Body.Close()
+	assert.Equal(t, http.StatusUnprocessableEntity, resp.StatusCode)
+	assert.Empty(t, ss.items)
+}
+
 func TestPredict_StoreError(t *testing.T) {
 	mm := &mockModel{fe: 2, be: 1}
 	ff := mockFetcher{out: []metrics.Daily{}}
 	ss := &mockStore{aerr: errors.New("db down")}
 
-	h, _ := New(mm, ff, ss, time.Second)
+	h, _ := New(context.Background(), mm, ff, ss, time.Second)
 	srv := httptest.NewServer(Routes(h))
 	defer srv.Close()
 
@@ -157,7 +266,7 @@ func TestListPredictions_Success(t *testing.T) {
 	ff := mockFetcher{out: []metrics.Daily{}}
 	ss := &mockStore{}
 
-	h, _ := New(mm, ff, ss, time.Second)
+	h, _ := New(context.Background(), mm, ff, ss, time.Second)
 	mux := Routes(h)
 
 	// seed
@@ -174,10 +283,970 @@ func TestListPredictions_Success(t *testing.T) {
 	mux.ServeHTTP(rec, req)
 	require.Equal(t, http.StatusOK, rec.Code)
 
-	var items []store.Prediction
-	require.NoError(t, json.NewDecoder(rec.Body).Decode(&items))
-	require.Len(t, items, 1)
-	assert.Equal(t, 5, items[0].FEPods)
-	assert.Equal(t, 4, items[0].BEPods)
-	assert.Equal(t, 10.0, items[0].Input.GMV)
+	var page store.Page
+	require.NoError(t, json.NewDecoder(rec.Body).Decode(&page))
+	require.Len(t, page.Items, 1)
+	assert.Equal(t, 5, page.Items[0].FEPods)
+	assert.Equal(t, 4, page.Items[0].BEPods)
+	assert.Equal(t, 10.0, page.Items[0].Input.GMV)
+	assert.Empty(t, page.NextCursor)
+}
+
+func TestListPredictions_FiltersAndPaginates(t *testing.T) {
+	ss := &mockStore{}
+	for i := 0; i < 5; i++ {
+		ss.items = append(ss.items, store.Prediction{
+			ID:        strconv.Itoa(i),
+			Timestamp: time.Date(2025, 1, 1+i, 12, 0, 0, 0, time.UTC),
+			FEPods:    i + 1,
+		})
+	}
+	h, err := New(context.Background(), &mockModel{}, mockFetcher{out: []metrics.Daily{}}, ss, time.Second)
+	require.NoError(t, err)
+	mux := Routes(h)
+
+	list := func(query string) store.Page {
+		t.Helper()
+		rec := httptest.NewRecorder()
+		mux.ServeHTTP(rec, httptest.NewRequest(http.MethodGet, "/predictions?"+query, nil))
+		require.Equal(t, http.StatusOK, rec.Code, rec.Body.String())
+		var page store.Page
+		require.NoError(t, json.NewDecoder(rec.Body).Decode(&page))
+		return page
+	}
+	ids := func(p store.Page) []string {
+		var out []string
+		for _, it := range p.Items {
+			out = append(out, it.ID)
+		}
+		return out
+	}
+
+	assert.Equal(t, []string{"1", "2", "3"}, ids(list("from=2025-01-02&to=2025-01-05")))
+	assert.Equal(t, []string{"3", "2"}, ids(list("min_fe_pods=2&max_fe_pods=4&order=desc&limit=2")))
+
+	first := list("limit=2")
+	assert.Equal(t, []string{"0", "1"}, ids(first))
+	require.NotEmpty(t, first.NextCursor)
+	second := list("limit=2&cursor=" + first.NextCursor)
+	assert.Equal(t, []string{"2", "3"}, ids(second))
+	last := list("limit=2&cursor=" + second.NextCursor)
+	assert.Equal(t, []string{"4"}, ids(last))
+	assert.Empty(t, last.NextCursor)
+}
+
+func TestListPredictions_BadParams(t *testing.T) {
+	h, err := New(context.Background(), &mockModel{}, mockFetcher{out: []metrics.Daily{}}, &mockStore{}, time.Second)
+	require.NoError(t, err)
+
+	for _, q := range []string{"from=yesterday", "limit=0", "limit=5000", "min_fe_pods=-1", "order=up", "cursor=!!", "kind=guess", "reconciled=maybe"} {
+		rec := httptest.NewRecorder()
+		Routes(h).ServeHTTP(rec, httptest.NewRequest(http.MethodGet, "/predictions?"+q, nil))
+		assert.Equal(t, http.StatusBadRequest, rec.Code, q)
+	}
+}
+
+func TestNew_RejectThresholdExceeded(t *testing.T) {
+	mm := &mockModel{}
+	ff := mockFetcher{out: []metrics.Daily{}, rep: fetcher.Report{RowsRead: 10, Rejected: make([]fetcher.RowIssue, 4)}}
+	ss := &mockStore{}
+
+	_, err := New(context.Background(), mm, ff, ss, time.Second, WithMaxRejectRatio(0.3))
+	require.ErrorIs(t, err, fetcher.ErrRejectThreshold)
+	assert.Nil(t, mm.trainedWith, "model must not be trained on rejected data")
+}
+
+func TestIngestReport_Success(t *testing.T) {
+	mm := &mockModel{}
+	ff := mockFetcher{
+		out: []metrics.Daily{},
+		rep: fetcher.Report{
+			Source:       "test",
+			RowsRead:     3,
+			RowsAccepted: 2,
+			Rejected:     []fetcher.RowIssue{{Row: 3, Reason: "bad date"}},
+		},
+	}
+	ss := &mockStore{}
+
+	h, err := New(context.Background(), mm, ff, ss, time.Second)
+	require.NoError(t, err)
+
+	rec := httptest.NewRecorder()
+	Routes(h).ServeHTTP(rec, httptest.NewRequest(http.MethodGet, "/ingest/report", nil))
+	require.Equal(t, http.StatusOK, rec.Code)
+
+	var got fetcher.Report
+	require.NoError(t, json.NewDecoder(rec.Body).Decode(&got))
+	assert.Equal(t, 3, got.RowsRead)
+	assert.Equal(t, 2, got.RowsAccepted)
+	require.Len(t, got.Rejected, 1)
+	assert.Equal(t, 3, got.Rejected[0].Row)
+}
+
+func TestAdminRetrain_NotRegisteredWithoutToken(t *testing.T) {
+	h, err := New(context.Background(), &mockModel{}, mockFetcher{out: []metrics.Daily{}}, &mockStore{}, time.Second)
+	require.NoError(t, err)
+
+	rec := httptest.NewRecorder()
+	Routes(h).ServeHTTP(rec, httptest.NewRequest(http.MethodPost, "/admin/retrain", nil))
+	assert.Equal(t, http.StatusNotFound, rec.Code)
+}
+
+func TestAdminRetrain_Unauthorized(t *testing.T) {
+	h, err := New(context.Background(), &mockModel{}, mockFetcher{out: []metrics.Daily{}}, &mockStore{}, time.Second,
+		WithAdminToken("s3cret"))
+	require.NoError(t, err)
+
+	for _, auth := range []string{"", "Bearer wrong", "s3cret"} {
+		rec := httptest.NewRecorder()
+		req := httptest.NewRequest(http.MethodPost, "/admin/retrain", nil)
+		if auth != "" {
+			req.Header.Set("Authorization", auth)
+		}
+		Routes(h).ServeHTTP(rec, req)
+		assert.Equal(t, http.StatusUnauthorized, rec.Code, auth)
+	}
+}
+
+func TestAdminRetrain_Success(t *testing.T) {
+	fe, be := 3, 2
+	day, err := metrics.NewDaily(time.Date(2025, 1, 1, 0, 0, 0, 0, time.UTC), 10, 1, 1, &fe, &be)
+	require.NoError(t, err)
+	noPods, err := metrics.NewDaily(time.Date(2025, 1, 2, 0, 0, 0, 0, time.UTC), 10, 1, 1, nil, nil)
+	require.NoError(t, err)
+
+	mm := &mockModel{}
+	ff := mockFetcher{
+		out: []metrics.Daily{day, noPods},
+		rep: fetcher.Report{RowsRead: 3, Rejected: []fetcher.RowIssue{{Row: 4, Reason: "bad"}}},
+	}
+	h, err := New(context.Background(), mm, ff, &mockStore{}, time.Second, WithAdminToken("s3cret"))
+	require.NoError(t, err)
+	first := h.Lineage().ModelVersion
+
+	rec := httptest.NewRecorder()
+	req := httptest.NewRequest(http.MethodPost, "/admin/retrain", nil)
+	req.Header.Set("Authorization", "Bearer s3cret")
+	Routes(h).ServeHTTP(rec, req)
+	require.Equal(t, http.StatusOK, rec.Code)
+
+	var sum RetrainSummary
+	require.NoError(t, json.NewDecoder(rec.Body).Decode(&sum))
+	assert.Equal(t, 1, sum.RowsUsed)
+	assert.Equal(t, 1, sum.RowsSkipped)
+	assert.Equal(t, 1, sum.RowsRejected)
+	assert.NotEmpty(t, sum.ModelVersion)
+	assert.NotEqual(t, first, sum.ModelVersion, "each retrain yields a new model version")
+	assert.Nil(t, sum.Model, "mock model does not implement model.Describer")
+}
+
+func TestAdminRetrain_ConflictWhileRunning(t *testing.T) {
+	h, err := New(context.Background(), &mockModel{}, mockFetcher{out: []metrics.Daily{}}, &mockStore{}, time.Second,
+		WithAdminToken("s3cret"))
+	require.NoError(t, err)
+
+	h.retrainMu.Lock() // simulate a retrain in progress
+	defer h.retrainMu.Unlock()
+
+	rec := httptest.NewRecorder()
+	req := httptest.NewRequest(http.MethodPost, "/admin/retrain", nil)
+	req.Header.Set("Authorization", "Bearer s3cret")
+	Routes(h).ServeHTTP(rec, req)
+	assert.Equal(t, http.StatusConflict, rec.Code)
+}
+
+func TestDescribeModel_NotSupported(t *testing.T) {
+	h, err := New(context.Background(), &mockModel{}, mockFetcher{out: []metrics.Daily{}}, &mockStore{}, time.Second)
+	require.NoError(t, err)
+
+	rec := httptest.NewRecorder()
+	Routes(h).ServeHTTP(rec, httptest.NewRequest(http.MethodGet, "/model", nil))
+	assert.Equal(t, http.StatusNotImplemented, rec.Code)
+}
+
+func TestDescribeModel_Success(t *testing.T) {
+	mm := &describingModel{desc: model.Description{
+		Type:         "linreg",
+		TrainingRows: 12,
+		FE: model.Regressor{
+			Features:     []string{"GMV"},
+			Coefficients: map[string]float64{"GMV": 0.5},
+			Intercept:    1,
+			R2:           0.9,
+		},
+	}}
+	h, err := New(context.Background(), mm, mockFetcher{out: []metrics.Daily{}}, &mockStore{}, time.Second)
+	require.NoError(t, err)
+
+	rec := httptest.NewRecorder()
+	Routes(h).ServeHTTP(rec, httptest.NewRequest(http.MethodGet, "/model", nil))
+	require.Equal(t, http.StatusOK, rec.Code)
+
+	var got struct {
+		ModelVersion string `json:"model_version"`
+		model.Description
+	}
+	require.NoError(t, json.NewDecoder(rec.Body).Decode(&got))
+	assert.Equal(t, h.Lineage().ModelVersion, got.ModelVersion)
+	assert.Equal(t, "linreg", got.Type)
+	assert.Equal(t, 12, got.TrainingRows)
+	assert.Equal(t, 0.5, got.FE.Coefficients["GMV"])
+	assert.Equal(t, 0.9, got.FE.R2)
+}
+
+func TestPredict_Explain(t *testing.T) {
+	mm := &explainingModel{ex: model.Explanation{
+		FE: model.TierExplanation{Raw: -0.4, Intercept: -2, Contributions: map[string]float64{"gmv": 1.6}, Pods: 1, ClampedToMin: true},
+		BE: model.TierExplanation{Raw: 3.2, Intercept: 1, Contributions: map[string]float64{"gmv": 2.2}, Pods: 3},
+	}}
+	h, err := New(context.Background(), mm, mockFetcher{out: []metrics.Daily{}}, &mockStore{}, time.Second)
+	require.NoError(t, err)
+
+	rec := httptest.NewRecorder()
+	req := httptest.NewRequest(http.MethodPost, "/predict?explain=true",
+		bytes.NewReader([]byte(`{"gmv":1,"users":1,"marketing_cost":1}`)))
+	Routes(h).ServeHTTP(rec, req)
+	require.Equal(t, http.StatusCreated, rec.Code)
+
+	var got struct {
+		store.Prediction
+		Explanation *model.Explanation `json:"explanation"`
+	}
+	require.NoError(t, json.NewDecoder(rec.Body).Decode(&got))
+	assert.Equal(t, 1, got.FEPods)
+	assert.Equal(t, 3, got.BEPods)
+	require.NotNil(t, got.Explanation)
+	assert.True(t, got.Explanation.FE.ClampedToMin)
+	assert.Equal(t, -0.4, got.Explanation.FE.Raw)
+	assert.Equal(t, 2.2, got.Explanation.BE.Contributions["gmv"])
+}
+
+func TestPredict_ExplainNotSupported(t *testing.T) {
+	h, err := New(context.Background(), &mockModel{fe: 1, be: 1}, mockFetcher{out: []metrics.Daily{}}, &mockStore{}, time.Second)
+	require.NoError(t, err)
+
+	rec := httptest.NewRecorder()
+	req := httptest.NewRequest(http.MethodPost, "/predict?explain=1",
+		bytes.NewReader([]byte(`{"gmv":1,"users":1,"marketing_cost":1}`)))
+	Routes(h).ServeHTTP(rec, req)
+	assert.Equal(t, http.StatusNotImplemented, rec.Code)
+}
+
+func TestPredict_WithoutExplainOmitsExplanation(t *testing.T) {
+	h, err := New(context.Background(), &mockModel{fe: 1, be: 1}, mockFetcher{out: []metrics.Daily{}}, &mockStore{}, time.Second)
+	require.NoError(t, err)
+
+	rec := httptest.NewRecorder()
+	req := httptest.NewRequest(http.MethodPost, "/predict",
+		bytes.NewReader([]byte(`{"gmv":1,"users":1,"marketing_cost":1}`)))
+	Routes(h).ServeHTTP(rec, req)
+	require.Equal(t, http.StatusCreated, rec.Code)
+	assert.NotContains(t, rec.Body.String(), "explanation")
+}
+
+func TestPredict_IntervalUpperBound(t *testing.T) {
+	mm := &intervalModel{mockModel{fe: 5, be: 3}}
+	ss := &mockStore{}
+	h, err := New(context.Background(), mm, mockFetcher{out: []metrics.Daily{}}, ss, time.Second)
+	require.NoError(t, err)
+
+	rec := httptest.NewRecorder()
+	req := httptest.NewRequest(http.MethodPost, "/predict?confidence=p90&bound=upper",
+		bytes.NewReader([]byte(`{"gmv":1,"users":1,"marketing_cost":1}`)))
+	Routes(h).ServeHTTP(rec, req)
+	require.Equal(t, http.StatusCreated, rec.Code)
+
+	var got store.Prediction
+	require.NoError(t, json.NewDecoder(rec.Body).Decode(&got))
+	require.NotNil(t, got.Interval)
+	assert.Equal(t, 0.9, got.Interval.Confidence)
+	assert.Equal(t, model.BoundUpper, got.Interval.Bound)
+	assert.Equal(t, 8, got.FEPods, "ceil(5+2.2)")
+	assert.Equal(t, 4, got.BEPods, "ceil(3+0.3)")
+
+	require.Len(t, ss.items, 1)
+	assert.Equal(t, got.Interval.FE.Upper, ss.items[0].Interval.FE.Upper, "interval is persisted")
+}
+
+func TestPredict_IntervalDefaultsToPoint(t *testing.T) {
+	mm := &intervalModel{mockModel{fe: 5, be: 3}}
+	h, err := New(context.Background(), mm, mockFetcher{out: []metrics.Daily{}}, &mockStore{}, time.Second)
+	require.NoError(t, err)
+
+	rec := httptest.NewRecorder()
+	req := httptest.NewRequest(http.MethodPost, "/predict?confidence=0.5",
+		bytes.NewReader([]byte(`{"gmv":1,"users":1,"marketing_cost":1}`)))
+	Routes(h).ServeHTTP(rec, req)
+	require.Equal(t, http.StatusCreated, rec.Code)
+
+	var got store.Prediction
+	require.NoError(t, json.NewDecoder(rec.Body).Decode(&got))
+	assert.Equal(t, 5, got.FEPods)
+	assert.Equal(t, 3, got.BEPods)
+	assert.Equal(t, model.BoundPoint, got.Interval.Bound)
+}
+
+func TestPredict_IntervalBadParams(t *testing.T) {
+	mm := &intervalModel{mockModel{fe: 5, be: 3}}
+	h, err := New(context.Background(), mm, mockFetcher{out: []metrics.Daily{}}, &mockStore{}, time.Second)
+	require.NoError(t, err)
+
+	for _, q := range []string{"confidence=abc", "confidence=p90&bound=max", "bound=upper"} {
+		rec := httptest.NewRecorder()
+		req := httptest.NewRequest(http.MethodPost, "/predict?"+q,
+			bytes.NewReader([]byte(`{"gmv":1,"users":1,"marketing_cost":1}`)))
+		Routes(h).ServeHTTP(rec, req)
+		assert.Equal(t, http.StatusBadRequest, rec.Code, q)
+	}
+}
+
+func TestPredict_IntervalNotSupported(t *testing.T) {
+	h, err := New(context.Background(), &mockModel{fe: 1, be: 1}, mockFetcher{out: []metrics.Daily{}}, &mockStore{}, time.Second)
+	require.NoError(t, err)
+
+	rec := httptest.NewRecorder()
+	req := httptest.NewRequest(http.MethodPost, "/predict?confidence=p90",
+		bytes.NewReader([]byte(`{"gmv":1,"users":1,"marketing_cost":1}`)))
+	Routes(h).ServeHTTP(rec, req)
+	assert.Equal(t, http.StatusNotImplemented, rec.Code)
+}
+
+func podDays(n int) []metrics.Daily {
+	out := make([]metrics.Daily, n)
+	for i := range out {
+		fe, be := i+1, i+1
+		d, err := metrics.NewDaily(time.Date(2025, 1, 1+i, 0, 0, 0, 0, time.UTC), float64(i), i, 1, &fe, &be)
+		if err != nil {
+			panic(err)
+		}
+		out[i] = d
+	}
+	return out
+}
+
+func TestEvaluateModel_NotConfigured(t *testing.T) {
+	h, err := New(context.Background(), &mockModel{}, mockFetcher{out: podDays(4)}, &mockStore{}, time.Second)
+	require.NoError(t, err)
+
+	rec := httptest.NewRecorder()
+	Routes(h).ServeHTTP(rec, httptest.NewRequest(http.MethodGet, "/model/evaluation", nil))
+	assert.Equal(t, http.StatusNotImplemented, rec.Code)
+}
+
+func TestEvaluateModel_Success(t *testing.T) {
+	factory := func() model.Model { return &mockModel{fe: 2, be: 2} }
+	h, err := New(context.Background(), &mockModel{}, mockFetcher{out: podDays(4)}, &mockStore{}, time.Second,
+		WithModelFactory(factory))
+	require.NoError(t, err)
+
+	rec := httptest.NewRecorder()
+	Routes(h).ServeHTTP(rec, httptest.NewRequest(http.MethodGet, "/model/evaluation?k=2&min_train=2", nil))
+	require.Equal(t, http.StatusOK, rec.Code)
+
+	var got struct {
+		ModelVersion string `json:"model_version"`
+		KFold        struct {
+			Folds int `json:"folds"`
+			Days  int `json:"days"`
+			FE    struct {
+				MAE float64 `json:"mae"`
+			} `json:"fe"`
+		} `json:"kfold"`
+		WalkForward struct {
+			Days int `json:"days"`
+		} `json:"walk_forward"`
+	}
+	require.NoError(t, json.NewDecoder(rec.Body).Decode(&got))
+	assert.Equal(t, h.Lineage().ModelVersion, got.ModelVersion)
+	assert.Equal(t, 2, got.KFold.Folds)
+	assert.Equal(t, 4, got.KFold.Days)
+	assert.InDelta(t, 1.0, got.KFold.FE.MAE, 1e-9) // actuals 1..4 vs constant 2
+	assert.Equal(t, 2, got.WalkForward.Days)
+}
+
+func TestEvaluateModel_BadParams(t *testing.T) {
+	factory := func() model.Model { return &mockModel{} }
+	h, err := New(context.Background(), &mockModel{}, mockFetcher{out: podDays(4)}, &mockStore{}, time.Second,
+		WithModelFactory(factory))
+	require.NoError(t, err)
+
+	for _, q := range []string{"k=zero", "k=0", "horizon=-1"} {
+		rec := httptest.NewRecorder()
+		Routes(h).ServeHTTP(rec, httptest.NewRequest(http.MethodGet, "/model/evaluation?"+q, nil))
+		assert.Equal(t, http.StatusBadRequest, rec.Code, q)
+	}
+
+	rec := httptest.NewRecorder()
+	Routes(h).ServeHTTP(rec, httptest.NewRequest(http.MethodGet, "/model/evaluation?k=10", nil))
+	assert.Equal(t, http.StatusUnprocessableEntity, rec.Code, "more folds than rows")
+}
+
+func TestGetPrediction(t *testing.T) {
+	ss := &mockStore{items: []store.Prediction{{ID: "abc", FEPods: 3, BEPods: 2}}}
+	h, err := New(context.Background(), &mockModel{}, mockFetcher{out: []metrics.Daily{}}, ss, time.Second)
+	require.NoError(t, err)
+
+	rec := httptest.NewRecorder()
+	Routes(h).ServeHTTP(rec, httptest.NewRequest(http.MethodGet, "/predictions/abc", nil))
+	require.Equal(t, http.StatusOK, rec.Code)
+	var got store.Prediction
+	require.NoError(t, json.NewDecoder(rec.Body).Decode(&got))
+	assert.Equal(t, ss.items[0], got)
+
+	rec = httptest.NewRecorder()
+	Routes(h).ServeHTTP(rec, httptest.NewRequest(http.MethodGet, "/predictions/nope", nil))
+	assert.Equal(t, http.StatusNotFound, rec.Code)
+}
+
+func TestDeletePrediction(t *testing.T) {
+	ss := &mockStore{items: []store.Prediction{{ID: "abc"}}}
+	h, err := New(context.Background(), &mockModel{}, mockFetcher{out: []metrics.Daily{}}, ss, time.Second)
+	require.NoError(t, err)
+
+	rec := httptest.NewRecorder()
+	Routes(h).ServeHTTP(rec, httptest.NewRequest(http.MethodDelete, "/predictions/abc", nil))
+	assert.Equal(t, http.StatusNoContent, rec.Code)
+	assert.Empty(t, ss.items)
+
+	rec = httptest.NewRecorder()
+	Routes(h).ServeHTTP(rec, httptest.NewRequest(http.MethodDelete, "/predictions/abc", nil))
+	assert.Equal(t, http.StatusNotFound, rec.Code)
+}
+
+func TestDeletePrediction_RequiresAdminTokenWhenConfigured(t *testing.T) {
+	ss := &mockStore{items: []store.Prediction{{ID: "abc"}}}
+	h, err := New(context.Background(), &mockModel{}, mockFetcher{out: []metrics.Daily{}}, ss, time.Second,
+		WithAdminToken("s3cret"))
+	require.NoError(t, err)
+
+	rec := httptest.NewRecorder()
+	Routes(h).ServeHTTP(rec, httptest.NewRequest(http.MethodDelete, "/predictions/abc", nil))
+	assert.Equal(t, http.StatusUnauthorized, rec.Code)
+	assert.Len(t, ss.items, 1)
+
+	rec = httptest.NewRecorder()
+	req := httptest.NewRequest(http.MethodDelete, "/predictions/abc", nil)
+	req.Header.Set("Authorization", "Bearer s3cret")
+	Routes(h).ServeHTTP(rec, req)
+	assert.Equal(t, http.StatusNoContent, rec.Code)
+}
+
+func TestRetrain_ReconcilesPredictedDays(t *testing.T) {
+	day := time.Date(2025, 11, 28, 0, 0, 0, 0, time.UTC)
+	ss := &mockStore{}
+	require.NoError(t, ss.Upsert(context.Background(), store.Prediction{
+		Timestamp: day, FEPods: 5, BEPods: 2, Source: store.SourceSheet, Kind: store.KindPredicted,
+	}))
+	fe, be := 7, 2
+	d, err := metrics.NewDaily(day, 10, 1, 1, &fe, &be)
+	require.NoError(t, err)
+
+	h, err := New(context.Background(), &mockModel{}, mockFetcher{out: []metrics.Daily{d}}, ss, time.Second)
+	require.NoError(t, err)
+	require.NotNil(t, ss.items[0].Reconciliation)
+	assert.Equal(t, -2, ss.items[0].Reconciliation.FEError)
+	assert.Equal(t, 0, ss.items[0].Reconciliation.BEError)
+
+	// Already reconciled: the next retrain has nothing to do.
+	sum, err := h.Retrain(context.Background())
+	require.NoError(t, err)
+	assert.Equal(t, 0, sum.Reconciled)
+}
+
+func TestAccuracy(t *testing.T) {
+	day := time.Date(2025, 11, 1, 0, 0, 0, 0, time.UTC)
+	ss := &mockStore{}
+	for i, fe := range []int{2, -1, 0} {
+		ss.items = append(ss.items, store.Prediction{
+			ID:        strconv.Itoa(i),
+			Timestamp: day.AddDate(0, 0, i),
+			FEPods:    5 + fe,
+			BEPods:    3,
+			Kind:      store.KindPredicted,
+			Reconciliation: &store.Reconciliation{
+				ActualFEPods: 5, ActualBEPods: 3, FEError: fe,
+			},
+		})
+	}
+	ss.items = append(ss.items, store.Prediction{ID: "open", Timestamp: day, Kind: store.KindPredicted})
+
+	h, err := New(context.Background(), &mockModel{}, mockFetcher{out: []metrics.Daily{}}, ss, time.Second)
+	require.NoError(t, err)
+
+	rec := httptest.NewRecorder()
+	Routes(h).ServeHTTP(rec, httptest.NewRequest(http.MethodGet, "/accuracy?from=2025-11-02&window=2", nil))
+	require.Equal(t, http.StatusOK, rec.Code)
+
+	var got struct {
+		Window int `json:"window"`
+		Days   int `json:"days"`
+		FE     struct {
+			MAE float64 `json:"mae"`
+		} `json:"fe"`
+		Points []struct {
+			FEError      int     `json:"fe_error"`
+			RollingFEMAE float64 `json:"rolling_fe_mae"`
+		} `json:"points"`
+	}
+	require.NoError(t, json.NewDecoder(rec.Body).Decode(&got))
+	assert.Equal(t, 2, got.Window)
+	assert.Equal(t, 2, got.Days)
+	assert.InDelta(t, 0.5, got.FE.MAE, 1e-9)
+	require.Len(t, got.Points, 2)
+	assert.Equal(t, -1, got.Points[0].FEError)
+	assert.InDelta(t, 0.5, got.Points[1].RollingFEMAE, 1e-9)
+
+	for _, q := range []string{"window=0", "from=soon"} {
+		rec := httptest.NewRecorder()
+		Routes(h).ServeHTTP(rec, httptest.NewRequest(http.MethodGet, "/accuracy?"+q, nil))
+		assert.Equal(t, http.StatusBadRequest, rec.Code, q)
+	}
+}
+
+func TestTraining_ReturnsTrainingFetch(t *testing.T) {
+	fe, be := 3, 2
+	d1, err := metrics.NewDaily(time.Date(2025, 1, 1, 0, 0, 0, 0, time.UTC), 10, 1, 1, &fe, &be)
+	require.NoError(t, err)
+	d2, err := metrics.NewDaily(time.Date(2025, 1, 2, 0, 0, 0, 0, time.UTC), 10, 1, 1, nil, nil)
+	require.NoError(t, err)
+	ff := mockFetcher{out: []metrics.Daily{d1, d2}, rep: fetcher.Report{Source: "csv", RowsRead: 2}}
+	mm := &mockModel{fe: 1, be: 1}
+	h, err := New(context.Background(), mm, ff, &mockStore{}, time.Second,
+		WithModelFactory(func() model.Model { return &mockModel{} }))
+	require.NoError(t, err)
+
+	tr := h.Training()
+	assert.Same(t, mm, tr.Model)
+	assert.Equal(t, []metrics.Daily{d1, d2}, tr.Data)
+	assert.Equal(t, "csv", tr.Report.Source)
+	assert.Equal(t, h.Lineage(), tr.Lineage)
+
+	_, err = h.Retrain(context.Background())
+	require.NoError(t, err)
+	tr = h.Training()
+	assert.NotSame(t, mm, tr.Model)
+	assert.Equal(t, h.Lineage(), tr.Lineage)
+}
+
+func TestPredict_RecordsLineage(t *testing.T) {
+	fe, be := 3, 2
+	d, err := metrics.NewDaily(time.Date(2025, 1, 1, 0, 0, 0, 0, time.UTC), 10, 1, 1, &fe, &be)
+	require.NoError(t, err)
+	ff := mockFetcher{out: []metrics.Daily{d}}
+	mm := &describingModel{mockModel: mockModel{fe: 1, be: 1}, desc: model.Description{Type: "linreg"}}
+	ss := &mockStore{}
+	h, err := New(context.Background(), mm, ff, ss, time.Second)
+	require.NoError(t, err)
+	first := h.Lineage()
+
+	predict := func() {
+		rec := httptest.NewRecorder()
+		Routes(h).ServeHTTP(rec, httptest.NewRequest(http.MethodPost, "/predict",
+			bytes.NewReader([]byte(`{"gmv":1,"users":1,"marketing_cost":1}`))))
+		require.Equal(t, http.StatusCreated, rec.Code)
+	}
+	predict()
+	_, err = h.Retrain(context.Background())
+	require.NoError(t, err)
+	predict()
+
+	require.Len(t, ss.items, 2)
+	got := ss.items[0].Lineage
+	require.NotNil(t, got)
+	assert.Equal(t, first, *got)
+	assert.Equal(t, "linreg", got.ModelType)
+	assert.Equal(t, metrics.Fingerprint(ff.out), got.DataFingerprint)
+	assert.False(t, got.TrainedAt.IsZero())
+	assert.NotEqual(t, first.ModelVersion, ss.items[1].Lineage.ModelVersion)
+	assert.Equal(t, first.DataFingerprint, ss.items[1].Lineage.DataFingerprint, "same data, same fingerprint")
+
+	rec := httptest.NewRecorder()
+	Routes(h).ServeHTTP(rec, httptest.NewRequest(http.MethodGet, "/predictions?model_version="+first.ModelVersion, nil))
+	require.Equal(t, http.StatusOK, rec.Code)
+	var page store.Page
+	require.NoError(t, json.NewDecoder(rec.Body).Decode(&page))
+	require.Len(t, page.Items, 1)
+	assert.Equal(t, ss.items[0].ID, page.Items[0].ID)
+}
+
+func TestPredictBatch(t *testing.T) {
+	ss := &mockStore{items: []store.Prediction{{ID: "taken"}}}
+	h, err := New(context.Background(), &mockModel{fe: 4, be: 2}, mockFetcher{out: []metrics.Daily{}}, ss, time.Second)
+	require.NoError(t, err)
+
+	body := `[
+		{"gmv":1,"users":2,"marketing_cost":3},
+		{"id":"a","date":"2025-12-01","gmv":10,"users":20,"marketing_cost":30},
+		{"id":"a","gmv":1,"users":1,"marketing_cost":1},
+		{"id":"taken","gmv":1,"users":1,"marketing_cost":1},
+		{"date":"yesterday","gmv":1,"users":1,"marketing_cost":1},
+		{"gmv":-1,"users":1}
+	]`
+	rec := httptest.NewRecorder()
+	Routes(h).ServeHTTP(rec, httptest.NewRequest(http.MethodPost, "/predict/batch", bytes.NewReader([]byte(body))))
+	require.Equal(t, http.StatusOK, rec.Code, rec.Body.String())
+
+	var got batchResponse
+	require.NoError(t, json.NewDecoder(rec.Body).Decode(&got))
+	assert.Equal(t, 2, got.Succeeded)
+	assert.Equal(t, 4, got.Failed)
+	require.Len(t, got.Results, 6)
+	for i, res := range got.Results {
+		assert.Equal(t, i, res.Index)
+	}
+
+	require.NotNil(t, got.Results[0].Prediction)
+	assert.NotEmpty(t, got.Results[0].Prediction.ID)
+	assert.Equal(t, 4, got.Results[0].Prediction.FEPods)
+
+	first := got.Results[1].Prediction
+	require.NotNil(t, first)
+	assert.Equal(t, "a", first.ID)
+	assert.Equal(t, time.Date(2025, 12, 1, 0, 0, 0, 0, time.UTC), first.Timestamp)
+	assert.Equal(t, 10.0, first.Input.GMV)
+	assert.Equal(t, store.SourceAPI, first.Source)
+	assert.Equal(t, store.KindPredicted, first.Kind)
+	assert.Equal(t, h.Lineage().ModelVersion, first.Lineage.ModelVersion)
+
+	assert.Equal(t, "duplicate id in batch", got.Results[2].Error)
+	assert.Equal(t, "id already exists", got.Results[3].Error)
+	assert.Contains(t, got.Results[4].Error, "invalid date")
+	assert.Equal(t, []invalidParam{
+		{Name: "marketing_cost", Reason: "is required"},
+	}, got.Results[5].InvalidParams)
+	for _, res := range got.Results[2:] {
+		assert.Nil(t, res.Prediction)
+	}
+
+	require.Len(t, ss.items, 3, "the successful items are stored")
+	assert.Equal(t, *first, ss.items[2])
+}
+
+func TestPredictBatch_BadRequests(t *testing.T) {
+	h, err := New(context.Background(), &mockModel{}, mockFetcher{out: []metrics.Daily{}}, &mockStore{}, time.Second,
+		WithMaxBatchSize(2))
+	require.NoError(t, err)
+
+	for _, body := range []string{
+		`{"gmv":1}`,
+		`[]`,
+		`[{"gmv":1},{"gmv":2},{"gmv":3}]`,
+	} {
+		rec := httptest.NewRecorder()
+		Routes(h).ServeHTTP(rec, httptest.NewRequest(http.MethodPost, "/predict/batch", bytes.NewReader([]byte(body))))
+		assert.Equal(t, http.StatusBadRequest, rec.Code, body)
+	}
+}
+
+func TestPredictBatch_StoreError(t *testing.T) {
+	ss := &mockStore{aerr: errors.New("db down")}
+	h, err := New(context.Background(), &mockModel{}, mockFetcher{out: []metrics.Daily{}}, ss, time.Second)
+	require.NoError(t, err)
+
+	rec := httptest.NewRecorder()
+	body := `[{"gmv":1,"users":1,"marketing_cost":1}]`
+	Routes(h).ServeHTTP(rec, httptest.NewRequest(http.MethodPost, "/predict/batch", bytes.NewReader([]byte(body))))
+	assert.Equal(t, http.StatusInternalServerError, rec.Code)
+}
+
+func TestPredict_InvalidBody(t *testing.T) {
+	h, err := New(context.Background(), &mockModel{}, mockFetcher{out: []metrics.Daily{}}, &mockStore{}, time.Second,
+		WithMaxBodyBytes(128))
+	require.NoError(t, err)
+
+	for _, tc := range []struct {
+		name, body string
+		code       int
+		params     []invalidParam
+	}{
+		{"empty object", `{}`, http.StatusBadRequest, []invalidParam{
+			{Name: "gmv", Reason: "is required"},
+			{Name: "users", Reason: "is required"},
+			{Name: "marketing_cost", Reason: "is required"},
+		}},
+		{"negative", `{"gmv":-1,"users":-2,"marketing_cost":0}`, http.StatusBadRequest, []invalidParam{
+			{Name: "gmv", Reason: metrics.ErrGmvNegative.Error()},
+			{Name: "users", Reason: metrics.ErrUsersNegative.Error()},
+		}},
+		{"unknown field", `{"gmv":1,"users":1,"marketing_cost":1,"gvm":2}`, http.StatusBadRequest, []invalidParam{
+			{Name: "gvm", Reason: "unknown field"},
+		}},
+		{"wrong type", `{"gmv":"1","users":1,"marketing_cost":1}`, http.StatusBadRequest, []invalidParam{
+			{Name: "gmv", Reason: "got JSON string, want float64"},
+		}},
+		{"trailing data", `{"gmv":1,"users":1,"marketing_cost":1} {}`, http.StatusBadRequest, nil},
+		{"malformed", `{`, http.StatusBadRequest, nil},
+		{"too large", `{"gmv":1,"users":1,"marketing_cost":1` + strings.Repeat(" ", 128) + `}`, http.StatusRequestEntityTooLarge, nil},
+	} {
+		t.Run(tc.name, func(t *testing.T) {
+			rec := httptest.NewRecorder()
+			Routes(h).ServeHTTP(rec, httptest.NewRequest(http.MethodPost, "/predict", strings.NewReader(tc.body)))
+			require.Equal(t, tc.code, rec.Code, rec.Body.String())
+			assert.Equal(t, "application/problem+json", rec.Header().Get("Content-Type"))
+
+			var got problem
+			require.NoError(t, json.NewDecoder(rec.Body).Decode(&got))
+			assert.Equal(t, tc.code, got.Status)
+			assert.Equal(t, "about:blank", got.Type)
+			assert.Equal(t, http.StatusText(tc.code), got.Title)
+			assert.Equal(t, tc.params, got.InvalidParams)
+		})
+	}
+}
+
+func TestPredict_OutOfDistribution(t *testing.T) {
+	mm := &profilingModel{mockModel: mockModel{fe: 3, be: 2}, profile: model.Profile{
+		GMV:           model.FeatureStats{Min: 100, Max: 1000},
+		Users:         model.FeatureStats{Min: 10, Max: 100},
+		MarketingCost: model.FeatureStats{Min: 0, Max: 50},
+	}}
+	in := `{"gmv":10000,"users":50,"marketing_cost":10}`
+	post := func(h *Handler, body string) *httptest.ResponseRecorder {
+		rec := httptest.NewRecorder()
+		Routes(h).ServeHTTP(rec, httptest.NewRequest(http.MethodPost, "/predict", strings.NewReader(body)))
+		return rec
+	}
+	newHandler := func(opts ...Option) *Handler {
+		h, err := New(context.Background(), mm, mockFetcher{out: []metrics.Daily{}}, &mockStore{}, time.Second, opts...)
+		require.NoError(t, err)
+		return h
+	}
+
+	t.Run("warn by default", func(t *testing.T) {
+		rec := post(newHandler(), in)
+		require.Equal(t, http.StatusCreated, rec.Code)
+		var got predictResponse
+		require.NoError(t, json.NewDecoder(rec.Body).Decode(&got))
+		assert.Equal(t, []string{"gmv 10000 is outside the training range [100, 1000]"}, got.Warnings)
+
+		rec = post(newHandler(), `{"gmv":500,"users":50,"marketing_cost":10}`)
+		require.Equal(t, http.StatusCreated, rec.Code)
+		assert.NotContains(t, rec.Body.String(), "warnings")
+	})
+
+	t.Run("allow", func(t *testing.T) {
+		rec := post(newHandler(WithOODPolicy(OODAllow)), in)
+		require.Equal(t, http.StatusCreated, rec.Code)
+		assert.NotContains(t, rec.Body.String(), "warnings")
+	})
+
+	t.Run("reject", func(t *testing.T) {
+		ss := &mockStore{}
+		h, err := New(context.Background(), mm, mockFetcher{out: []metrics.Daily{}}, ss, time.Second, WithOODPolicy(OODReject))
+		require.NoError(t, err)
+		rec := post(h, in)
+		require.Equal(t, http.StatusUnprocessableEntity, rec.Code)
+		var got problem
+		require.NoError(t, json.NewDecoder(rec.Body).Decode(&got))
+		assert.Equal(t, []invalidParam{{Name: "gmv", Reason: "gmv 10000 is outside the training range [100, 1000]"}}, got.InvalidParams)
+		assert.Empty(t, ss.items)
+
+		rec = httptest.NewRecorder()
+		body := `[{"gmv":500,"users":50,"marketing_cost":10},` + in + `]`
+		Routes(h).ServeHTTP(rec, httptest.NewRequest(http.MethodPost, "/predict/batch", strings.NewReader(body)))
+		require.Equal(t, http.StatusOK, rec.Code)
+		var batch batchResponse
+		require.NoError(t, json.NewDecoder(rec.Body).Decode(&batch))
+		assert.Equal(t, 1, batch.Succeeded)
+		assert.Equal(t, errOutOfRange, batch.Results[1].Error)
+		assert.Len(t, ss.items, 1)
+	})
+}
+
+func TestForecast(t *testing.T) {
+	ss := &mockStore{}
+	// podDays covers Jan 1–10; a sheet row for Jan 12 carries KPIs only.
+	data := append(podDays(10), metrics.Daily{Date: time.Date(2025, 1, 12, 0, 0, 0, 0, time.UTC), GMV: 50, Users: 5, MarketingCost: 1})
+	mm := &profilingModel{mockModel: mockModel{fe: 4, be: 2}, profile: model.NewProfile(podDays(10))}
+	h, err := New(context.Background(), mm, mockFetcher{out: data}, ss, time.Second)
+	require.NoError(t, err)
+
+	get := func() forecastResponse {
+		rec := httptest.NewRecorder()
+		Routes(h).ServeHTTP(rec, httptest.NewRequest(http.MethodGet, "/forecast?from=2025-01-11&to=2025-01-13", nil))
+		require.Equal(t, http.StatusOK, rec.Code, rec.Body.String())
+		var got forecastResponse
+		require.NoError(t, json.NewDecoder(rec.Body).Decode(&got))
+		return got
+	}
+	got := get()
+	require.Len(t, got.Days, 3)
+	for i, d := range got.Days {
+		assert.Equal(t, time.Date(2025, 1, 11+i, 0, 0, 0, 0, time.UTC), d.Timestamp)
+		assert.Equal(t, store.SourceForecast, d.Source)
+		assert.Equal(t, store.KindPredicted, d.Kind)
+		assert.Equal(t, 4, d.FEPods)
+		require.NotNil(t, d.Lineage)
+		assert.Equal(t, h.Lineage().ModelVersion, d.Lineage.ModelVersion)
+	}
+	assert.Equal(t, "extrapolated", got.Days[0].KPIs)
+	assert.Equal(t, "sheet", got.Days[1].KPIs)
+	assert.Equal(t, 50.0, got.Days[1].Input.GMV)
+	assert.NotEmpty(t, got.Days[1].Warnings, "GMV 50 is outside the training range")
+	for _, d := range got.Days {
+		assert.True(t, d.Stored)
+		assert.False(t, d.InSample)
+	}
+	require.Len(t, ss.items, 3)
+
+	// The first forecast of a day is kept.
+	stored := append([]store.Prediction(nil), ss.items...)
+	mm.fe = 9
+	again := get()
+	for i, d := range again.Days {
+		assert.Equal(t, got.Days[i].ID, d.ID)
+		assert.Equal(t, 9, d.FEPods)
+		assert.False(t, d.Stored)
+	}
+	assert.Equal(t, stored, ss.items)
+}
+
+func TestForecast_DoesNotStoreInSampleDays(t *testing.T) {
+	ss := &mockStore{}
+	h, err := New(context.Background(), &mockModel{fe: 4, be: 2}, mockFetcher{out: podDays(10)}, ss, time.Second)
+	require.NoError(t, err)
+
+	rec := httptest.NewRecorder()
+	Routes(h).ServeHTTP(rec, httptest.NewRequest(http.MethodGet, "/forecast?from=2025-01-09&to=2025-01-11", nil))
+	require.Equal(t, http.StatusOK, rec.Code, rec.Body.String())
+	var got forecastResponse
+	require.NoError(t, json.NewDecoder(rec.Body).Decode(&got))
+	require.Len(t, got.Days, 3)
+
+	for _, d := range got.Days[:2] {
+		assert.True(t, d.InSample)
+		assert.False(t, d.Stored)
+		assert.Empty(t, d.ID)
+	}
+	assert.True(t, got.Days[2].Stored)
+	require.Len(t, ss.items, 1)
+	assert.Equal(t, time.Date(2025, 1, 11, 0, 0, 0, 0, time.UTC), ss.items[0].Timestamp)
+}
+
+func TestForecast_BadParams(t *testing.T) {
+	h, err := New(context.Background(), &mockModel{}, mockFetcher{out: podDays(3)}, &mockStore{}, time.Second)
+	require.NoError(t, err)
+
+	for _, q := range []string{
+		"",
+		"from=2025-02-01",
+		"from=nope&to=2025-02-01",
+		"from=2025-02-02&to=2025-02-01",
+		"from=2025-01-01&to=2026-01-02",
+	} {
+		rec := httptest.NewRecorder()
+		Routes(h).ServeHTTP(rec, httptest.NewRequest(http.MethodGet, "/forecast?"+q, nil))
+		assert.Equal(t, http.StatusBadRequest, rec.Code, q)
+	}
+}
+
+func TestForecast_NoHistory(t *testing.T) {
+	h, err := New(context.Background(), &mockModel{}, mockFetcher{out: []metrics.Daily{}}, &mockStore{}, time.Second)
+	require.NoError(t, err)
+
+	rec := httptest.NewRecorder()
+	Routes(h).ServeHTTP(rec, httptest.NewRequest(http.MethodGet, "/forecast?from=2025-02-01&to=2025-02-02", nil))
+	assert.Equal(t, http.StatusUnprocessableEntity, rec.Code)
+}
+
+func TestEvaluateModel_CachedPerModelVersion(t *testing.T) {
+	trained := 0
+	factory := func() model.Model { trained++; return &mockModel{fe: 2, be: 2} }
+	h, err := New(context.Background(), &mockModel{}, mockFetcher{out: podDays(4)}, &mockStore{}, time.Second,
+		WithModelFactory(factory), WithAdminToken("s3cret"))
+	require.NoError(t, err)
+
+	get := func(q string) int {
+		rec := httptest.NewRecorder()
+		req := httptest.NewRequest(http.MethodGet, "/model/evaluation?"+q, nil)
+		req.Header.Set("Authorization", "Bearer s3cret")
+		Routes(h).ServeHTTP(rec, req)
+		require.Equal(t, http.StatusOK, rec.Code)
+		return trained
+	}
+	first := get("k=2&min_train=2")
+	require.Positive(t, first)
+	assert.Equal(t, first, get("k=2&min_train=2"), "same model and parameters")
+	assert.Greater(t, get("k=4&min_train=2"), first, "new parameters")
+
+	_, err = h.Retrain(context.Background())
+	require.NoError(t, err)
+	n := trained
+	assert.Greater(t, get("k=4&min_train=2"), n, "new model version")
+
+	rec := httptest.NewRecorder()
+	Routes(h).ServeHTTP(rec, httptest.NewRequest(http.MethodGet, "/model/evaluation", nil))
+	assert.Equal(t, http.StatusUnauthorized, rec.Code)
+}
+
+func TestRetrain_SwapsFreshModelWithLineage(t *testing.T) {
+	initial := &mockModel{fe: 1, be: 1}
+	built := 0
+	factory := func() model.Model { built++; return &mockModel{fe: model.FEPods(1 + built), be: 1} }
+	h, err := New(context.Background(), initial, mockFetcher{out: podDays(3)}, &mockStore{}, time.Second,
+		WithModelFactory(factory))
+	require.NoError(t, err)
+	first := h.Lineage()
+
+	predict := func() store.Prediction {
+		rec := httptest.NewRecorder()
+		Routes(h).ServeHTTP(rec, httptest.NewRequest(http.MethodPost, "/predict",
+			strings.NewReader(`{"gmv":1,"users":1,"marketing_cost":1}`)))
+		require.Equal(t, http.StatusCreated, rec.Code)
+		var got store.Prediction
+		require.NoError(t, json.NewDecoder(rec.Body).Decode(&got))
+		return got
+	}
+	before := predict()
+	assert.Equal(t, 1, before.FEPods)
+	assert.Equal(t, first.ModelVersion, before.Lineage.ModelVersion)
+
+	_, err = h.Retrain(context.Background())
+	require.NoError(t, err)
+
+	after := predict()
+	assert.Equal(t, 2, after.FEPods, "served by the freshly trained model")
+	assert.Equal(t, h.Lineage().ModelVersion, after.Lineage.ModelVersion)
+	assert.NotEqual(t, first.ModelVersion, after.Lineage.ModelVersion)
+}
+
+func TestPredictBatch_ForecasterUsesItemDate(t *testing.T) {
+	mm := &forecastingModel{mockModel: mockModel{fe: 99, be: 2}}
+	h, err := New(context.Background(), mm, mockFetcher{out: []metrics.Daily{}}, &mockStore{}, time.Second)
+	require.NoError(t, err)
+
+	body := `[
+		{"date":"2025-12-07","gmv":1,"users":1,"marketing_cost":1},
+		{"gmv":1,"users":1,"marketing_cost":1}
+	]`
+	rec := httptest.NewRecorder()
+	Routes(h).ServeHTTP(rec, httptest.NewRequest(http.MethodPost, "/predict/batch", strings.NewReader(body)))
+	require.Equal(t, http.StatusOK, rec.Code, rec.Body.String())
+
+	var got batchResponse
+	require.NoError(t, json.NewDecoder(rec.Body).Decode(&got))
+	require.Equal(t, 2, got.Succeeded)
+	assert.Equal(t, 7, got.Results[0].Prediction.FEPods, "forecast for the item date")
+	assert.Equal(t, 99, got.Results[1].Prediction.FEPods, "no date goes through Predict")
+}
+
+func TestPredictBatch_UnstorableDate(t *testing.T) {
+	ss := &mockStore{}
+	h, err := New(context.Background(), &mockModel{}, mockFetcher{out: []metrics.Daily{}}, ss, time.Second)
+	require.NoError(t, err)
+
+	body := `[
+		{"date":"2025-12-01","gmv":1,"users":1,"marketing_cost":1},
+		{"date":"2300-01-01","gmv":1,"users":1,"marketing_cost":1}
+	]`
+	rec := httptest.NewRecorder()
+	Routes(h).ServeHTTP(rec, httptest.NewRequest(http.MethodPost, "/predict/batch", strings.NewReader(body)))
+	require.Equal(t, http.StatusUnprocessableEntity, rec.Code)
+
+	var got problem
+	require.NoError(t, json.NewDecoder(rec.Body).Decode(&got))
+	require.Len(t, got.InvalidParams, 1)
+	assert.Equal(t, "[1].date", got.InvalidParams[0].Name)
+	assert.Empty(t, ss.items)
 }
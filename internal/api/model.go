@@ -0,0 +1,37 @@
+package api
+
+import (
+	"net/http"
+
+	"github.com/thisiscetin/podpredict/internal/model"
+)
+
+// modelResponse is the body of GET /model.
+type modelResponse struct {
+	ModelVersion string `json:"model_version"`
+	model.Description
+}
+
+// GET /model
+// Returns: feature names, coefficients, intercept, R², training row count
+// and trained-at time of the FE and BE regressors
+func (h *Handler) DescribeModel(w http.ResponseWriter, r *http.Request) {
+	if r.Method != http.MethodGet {
+		writeError(w, http.StatusMethodNotAllowed, "method not allowed")
+		return
+	}
+
+	cur := h.current.Load()
+	d, ok := cur.model.(model.Describer)
+	if !ok {
+		writeError(w, http.StatusNotImplemented, "model does not support introspection")
+		return
+	}
+	desc, err := d.Describe()
+	if err != nil {
+		writeError(w, http.StatusServiceUnavailable, "describing model failed: "+err.Error())
+		return
+	}
+
+	writeJSON(w, http.StatusOK, modelResponse{ModelVersion: cur.lineage.ModelVersion, Description: desc})
+}
@@ -0,0 +1,71 @@
+package api
+
+import (
+	"net/http"
+
+	"github.com/thisiscetin/podpredict/internal/model"
+)
+
+// OODPolicy decides how predictions treat out-of-distribution inputs:
+// features outside the training range of the current model.
+type OODPolicy string
+
+const (
+	// OODAllow predicts without checking the training range.
+	OODAllow OODPolicy = "allow"
+	// OODWarn predicts and lists the out-of-range features under warnings.
+	OODWarn OODPolicy = "warn"
+	// OODReject refuses to predict with 422 Unprocessable Entity.
+	OODReject OODPolicy = "reject"
+)
+
+// errOutOfRange describes inputs rejected under OODReject.
+const errOutOfRange = "input outside the training range"
+
+// outOfRange returns the features of in outside the training range of m.
+// It is nil under OODAllow and for models that do not implement
+// model.Profiler or are untrained.
+func (h *Handler) outOfRange(m model.Model, in *model.Features) []model.RangeViolation {
+	if h.oodPolicy == OODAllow {
+		return nil
+	}
+	p, ok := m.(model.Profiler)
+	if !ok {
+		return nil
+	}
+	prof, err := p.Profile()
+	if err != nil {
+		return nil
+	}
+	return prof.OutOfRange(in)
+}
+
+// rangeParams lists vs as invalid request fields.
+func rangeParams(vs []model.RangeViolation) []invalidParam {
+	params := make([]invalidParam, len(vs))
+	for i, v := range vs {
+		params[i] = invalidParam{Name: v.Feature, Reason: v.String()}
+	}
+	return params
+}
+
+// rangeWarnings describes vs for the warnings of a prediction.
+func rangeWarnings(vs []model.RangeViolation) []string {
+	if vs == nil {
+		return nil
+	}
+	out := make([]string, len(vs))
+	for i, v := range vs {
+		out[i] = v.String()
+	}
+	return out
+}
+
+// writeOutOfRange rejects a request under OODReject.
+func writeOutOfRange(w http.ResponseWriter, vs []model.RangeViolation) {
+	writeProblem(w, problem{
+		Status:        http.StatusUnprocessableEntity,
+		Detail:        errOutOfRange,
+		InvalidParams: rangeParams(vs),
+	})
+}
@@ -0,0 +1,59 @@
+package api
+
+import "github.com/thisiscetin/podpredict/internal/model"
+
+// Option customizes a Handler created by New.
+type Option func(*Handler)
+
+// WithMaxRejectRatio makes New and Retrain fail when the fraction of source
+// rows rejected during ingestion exceeds ratio. The default of 1 never fails.
+func WithMaxRejectRatio(ratio float64) Option {
+	return func(h *Handler) {
+		h.maxRejectRatio = ratio
+	}
+}
+
+// WithAdminToken enables the /admin routes, authenticated with the given
+// bearer token. Without it the admin routes are not registered.
+func WithAdminToken(token string) Option {
+	return func(h *Handler) {
+		h.adminToken = token
+	}
+}
+
+// WithModelFactory makes Retrain train a fresh model built by f, swapped in
+// atomically with its lineage, and enables GET /model/evaluation, which
+// backtests models built by f on the data the current model was trained on.
+func WithModelFactory(f model.Factory) Option {
+	return func(h *Handler) {
+		h.newModel = f
+	}
+}
+
+// WithMaxBatchSize limits the number of items accepted by POST
+// /predict/batch. Values below 1 keep DefaultMaxBatchSize.
+func WithMaxBatchSize(n int) Option {
+	return func(h *Handler) {
+		if n > 0 {
+			h.maxBatchSize = n
+		}
+	}
+}
+
+// WithMaxBodyBytes limits the size of JSON request bodies; larger ones are
+// rejected with 413. Values below 1 keep DefaultMaxBodyBytes.
+func WithMaxBodyBytes(n int64) Option {
+	return func(h *Handler) {
+		if n > 0 {
+			h.maxBodyBytes = n
+		}
+	}
+}
+
+// WithOODPolicy sets how predictions treat inputs outside the training
+// range of models implementing model.Profiler. The default is OODWarn.
+func WithOODPolicy(p OODPolicy) Option {
+	return func(h *Handler) {
+		h.oodPolicy = p
+	}
+}
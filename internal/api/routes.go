@@ -6,7 +6,18 @@ import "net/http"
 func Routes(h *Handler) *http.ServeMux {
 	mux := http.NewServeMux()
 	mux.HandleFunc("POST /predict", h.Predict)
+	mux.HandleFunc("POST /predict/batch", h.PredictBatch)
 	mux.HandleFunc("GET /predictions", h.ListPredictions)
+	mux.HandleFunc("GET /predictions/{id}", h.GetPrediction)
+	mux.HandleFunc("DELETE /predictions/{id}", h.adminIfConfigured(h.DeletePrediction))
+	mux.HandleFunc("GET /forecast", h.Forecast)
+	mux.HandleFunc("GET /accuracy", h.Accuracy)
+	mux.HandleFunc("GET /model", h.DescribeModel)
+	mux.HandleFunc("GET /model/evaluation", h.adminIfConfigured(h.EvaluateModel))
+	mux.HandleFunc("GET /ingest/report", h.IngestReport)
 	mux.HandleFunc("GET /healthz", h.HealthCheck)
+	if h.adminToken != "" {
+		mux.HandleFunc("POST /admin/retrain", h.requireAdmin(h.AdminRetrain))
+	}
 	return mux
 }
@@ -3,10 +3,44 @@ package api
 import (
 	"encoding/json"
 	"net/http"
+
+	"github.com/thisiscetin/podpredict/internal/model"
+	"github.com/thisiscetin/podpredict/internal/store"
 )
 
-type errorResponse struct {
-	Error string `json:"error"`
+// predictResponse is the body of POST /predict.
+type predictResponse struct {
+	store.Prediction
+	Explanation *model.Explanation `json:"explanation,omitempty"`
+	// Warnings flags inputs outside the training range under OODWarn.
+	Warnings []string `json:"warnings,omitempty"`
+}
+
+// batchItem is one element of the POST /predict/batch body.
+type batchItem struct {
+	// ID is an optional client-chosen prediction ID.
+	ID string `json:"id,omitempty"`
+	// Date is an optional prediction timestamp, RFC 3339 or YYYY-MM-DD.
+	Date string `json:"date,omitempty"`
+	featuresInput
+}
+
+// batchResult is the outcome of one batchItem; exactly one of Prediction
+// and Error is set. InvalidParams details an Error caused by invalid features.
+type batchResult struct {
+	Index         int               `json:"index"`
+	ID            string            `json:"id,omitempty"`
+	Prediction    *store.Prediction `json:"prediction,omitempty"`
+	Error         string            `json:"error,omitempty"`
+	InvalidParams []invalidParam    `json:"invalid-params,omitempty"`
+	Warnings      []string          `json:"warnings,omitempty"`
+}
+
+// batchResponse is the body of POST /predict/batch.
+type batchResponse struct {
+	Results   []batchResult `json:"results"`
+	Succeeded int           `json:"succeeded"`
+	Failed    int           `json:"failed"`
 }
 
 func writeJSON(w http.ResponseWriter, code int, v any) {
@@ -15,6 +49,20 @@ func writeJSON(w http.ResponseWriter, code int, v any) {
 	_ = json.NewEncoder(w).Encode(v)
 }
 
-func writeError(w http.ResponseWriter, code int, msg string) {
-	writeJSON(w, code, errorResponse{Error: msg})
+// forecastDay is one day of the GET /forecast body.
+type forecastDay struct {
+	store.Prediction
+	// KPIs tells where the input came from: sheet, extrapolated or none.
+	KPIs string `json:"kpis"`
+	// Warnings flags inputs outside the training range under OODWarn.
+	Warnings []string `json:"warnings,omitempty"`
+	// Stored is set when this request stored the first forecast of the day.
+	Stored bool `json:"stored"`
+	// InSample marks days within the training data, which are not stored.
+	InSample bool `json:"in_sample,omitempty"`
+}
+
+// forecastResponse is the body of GET /forecast.
+type forecastResponse struct {
+	Days []forecastDay `json:"days"`
 }
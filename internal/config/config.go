@@ -2,14 +2,93 @@ package config
 
 import (
 	"fmt"
+	"math"
 	"os"
+	"strconv"
+	"strings"
 	"time"
+
+	"github.com/thisiscetin/podpredict/internal/fetcher"
 )
 
 const (
 	DefaultAddr          = ":7000"
+	DefaultEnvVarSource  = "DATA_SOURCE"
 	DefaultEnvVarCreds   = "GOOGLE_SHEETS_CREDENTIALS"
 	DefaultEnvVarSheetID = "GOOGLE_SHEETS_SPREADSHEET_ID"
+	DefaultEnvVarCSVPath = "CSV_PATH"
+
+	DefaultEnvVarSheetName  = "GOOGLE_SHEETS_SHEET_NAME"
+	DefaultEnvVarSheetRange = "GOOGLE_SHEETS_RANGE"
+	DefaultEnvVarDateLayout = "DATE_LAYOUT"
+	DefaultEnvVarColumns    = "COLUMN_MAPPING"
+
+	DefaultEnvVarMaxRejectRatio = "INGEST_MAX_REJECT_RATIO"
+
+	DefaultEnvVarFetchTimeout     = "FETCH_TIMEOUT"
+	DefaultEnvVarFetchMaxAttempts = "FETCH_MAX_ATTEMPTS"
+	DefaultEnvVarFetchBackoff     = "FETCH_BACKOFF"
+	DefaultEnvVarBreakerThreshold = "FETCH_BREAKER_THRESHOLD"
+	DefaultEnvVarBreakerCooldown  = "FETCH_BREAKER_COOLDOWN"
+
+	DefaultEnvVarRetrainInterval = "RETRAIN_INTERVAL"
+	DefaultEnvVarRetrainCron     = "RETRAIN_CRON"
+
+	DefaultEnvVarAdminToken = "ADMIN_TOKEN"
+
+	DefaultEnvVarMaxBatchSize = "PREDICT_MAX_BATCH_SIZE"
+	DefaultEnvVarMaxBodyBytes = "MAX_BODY_BYTES"
+	DefaultEnvVarOODPolicy    = "OOD_POLICY"
+
+	DefaultEnvVarModel       = "MODEL"
+	DefaultEnvVarModelLambda = "MODEL_LAMBDA"
+	DefaultEnvVarModelFolds  = "MODEL_CV_FOLDS"
+	DefaultEnvVarModelSeed   = "MODEL_SEED"
+
+	DefaultEnvVarGBTTrees         = "GBT_TREES"
+	DefaultEnvVarGBTMaxDepth      = "GBT_MAX_DEPTH"
+	DefaultEnvVarGBTLearningRate  = "GBT_LEARNING_RATE"
+	DefaultEnvVarGBTEarlyStopping = "GBT_EARLY_STOPPING_ROUNDS"
+
+	DefaultEnvVarForestTrees = "FOREST_TREES"
+	DefaultEnvVarHolidays    = "HOLIDAYS"
+
+	DefaultEnvVarStore      = "STORE"
+	DefaultEnvVarSQLitePath = "SQLITE_PATH"
+	DefaultSQLitePath       = "podpredict.db"
+	DefaultEnvVarJSONLPath  = "JSONL_PATH"
+	DefaultEnvVarJSONLMax   = "JSONL_MAX_SIZE"
+	DefaultJSONLPath        = "predictions.jsonl"
+)
+
+// Supported data sources for training metrics.
+const (
+	SourceGSheets = "gsheets"
+	SourceCSV     = "csv"
+)
+
+// Supported models.
+const (
+	ModelLinreg      = "linreg"
+	ModelRidge       = "ridge"
+	ModelLasso       = "lasso"
+	ModelGBT         = "gbt"
+	ModelForest      = "forest"
+	ModelHoltWinters = "holtwinters"
+)
+
+// Supported out-of-distribution policies, matching api.OODPolicy.
+const (
+	OODAllow  = "allow"
+	OODWarn   = "warn"
+	OODReject = "reject"
+)
+
+// Supported prediction store backends.
+const (
+	StoreMemory = "memory"
+	StoreSQLite = "sqlite"
+	StoreJSONL  = "jsonl"
 )
 
 type Config struct {
@@ -18,27 +97,301 @@ type Config struct {
 	ReadTimeout   time.Duration
 	WriteTimeout  time.Duration
 	IdleTimeout   time.Duration
+	Source        string
 	CredsJSON     []byte
 	SpreadsheetID string
+	SheetName     string
+	SheetRange    string
+	CSVPath       string
+	Layout        fetcher.Layout
+	// MaxRejectRatio is the highest fraction of rejected source rows
+	// tolerated before startup and retraining fail. 1 disables the check.
+	MaxRejectRatio float64
+
+	// FetchMaxAttempts is the number of attempts per fetch on transient errors.
+	FetchMaxAttempts int
+	// FetchBackoff is the initial delay between fetch attempts; it doubles per retry.
+	FetchBackoff time.Duration
+	// BreakerThreshold is the number of consecutive failed fetches that opens
+	// the circuit breaker. 0 disables it.
+	BreakerThreshold int
+	// BreakerCooldown is how long the circuit stays open.
+	BreakerCooldown time.Duration
+
+	// RetrainInterval schedules background retraining at a fixed period.
+	// 0 disables it unless RetrainCron is set.
+	RetrainInterval time.Duration
+	// RetrainCron schedules background retraining with a standard 5-field
+	// cron expression. It takes precedence over RetrainInterval.
+	RetrainCron string
+
+	// AdminToken is the bearer token for /admin routes. Empty disables them.
+	AdminToken string
+
+	// MaxBatchSize limits the items of a POST /predict/batch request.
+	// 0 keeps the API default.
+	MaxBatchSize int
+	// MaxBodyBytes limits the size of JSON request bodies. 0 keeps the API default.
+	MaxBodyBytes int
+	// OODPolicy is how predictions treat inputs outside the training range:
+	// OODAllow, OODWarn (default) or OODReject.
+	OODPolicy string
+
+	// Model selects the model implementation (ModelLinreg, ModelRidge,
+	// ModelLasso, ModelGBT, ModelForest or ModelHoltWinters).
+	Model string
+	// ModelLambda is the regularization strength of ModelRidge and
+	// ModelLasso. 0 selects it by cross-validation.
+	ModelLambda float64
+	// ModelFolds is the number of cross-validation folds used to select
+	// ModelLambda. 0 keeps the model default.
+	ModelFolds int
+	// ModelSeed seeds the randomized parts of training, such as the
+	// validation split of ModelGBT and the bootstrap samples of ModelForest.
+	ModelSeed int
+
+	// GBTTrees, GBTMaxDepth, GBTLearningRate and GBTEarlyStoppingRounds
+	// configure ModelGBT. 0 keeps the model defaults; a negative
+	// GBTEarlyStoppingRounds disables early stopping.
+	GBTTrees               int
+	GBTMaxDepth            int
+	GBTLearningRate        float64
+	GBTEarlyStoppingRounds int
+	// ForestTrees is the number of trees of ModelForest. 0 keeps the model default.
+	ForestTrees int
+	// Holidays lists the days ModelHoltWinters models as holidays.
+	Holidays []time.Time
+
+	// Store selects the prediction store backend (StoreMemory, StoreSQLite or StoreJSONL).
+	Store string
+	// SQLitePath is the database file used by StoreSQLite.
+	SQLitePath string
+	// JSONLPath is the append-only file used by StoreJSONL.
+	JSONLPath string
+	// JSONLMaxSize is the file size in bytes that triggers compaction and
+	// rotation of the StoreJSONL file. 0 disables it.
+	JSONLMaxSize int
 }
 
 func Load() (Config, error) {
-	creds := os.Getenv(DefaultEnvVarCreds)
-	if creds == "" {
-		return Config{}, fmt.Errorf("%s is required", DefaultEnvVarCreds)
-	}
-	id := os.Getenv(DefaultEnvVarSheetID)
-	if id == "" {
-		return Config{}, fmt.Errorf("%s is required", DefaultEnvVarSheetID)
-	}
-
-	return Config{
-		Addr:          DefaultAddr,
-		FetchTimeout:  5 * time.Second,
-		ReadTimeout:   5 * time.Second,
-		WriteTimeout:  10 * time.Second,
-		IdleTimeout:   60 * time.Second,
-		CredsJSON:     []byte(creds),
-		SpreadsheetID: id,
-	}, nil
+	cfg := Config{
+		Addr:         DefaultAddr,
+		FetchTimeout: 5 * time.Second,
+		ReadTimeout:  5 * time.Second,
+		WriteTimeout: 10 * time.Second,
+		IdleTimeout:  60 * time.Second,
+		Source:       os.Getenv(DefaultEnvVarSource),
+
+		MaxRejectRatio: 1,
+
+		FetchMaxAttempts: 3,
+		FetchBackoff:     500 * time.Millisecond,
+		BreakerThreshold: 5,
+		BreakerCooldown:  time.Minute,
+	}
+	if cfg.Source == "" {
+		cfg.Source = SourceGSheets
+	}
+
+	cols, err := fetcher.ParseColumns(os.Getenv(DefaultEnvVarColumns))
+	if err != nil {
+		return Config{}, fmt.Errorf("%s: %w", DefaultEnvVarColumns, err)
+	}
+	cfg.Layout = fetcher.Layout{
+		DateLayout: os.Getenv(DefaultEnvVarDateLayout),
+		Columns:    cols,
+	}
+
+	if err := durationEnv(DefaultEnvVarFetchTimeout, &cfg.FetchTimeout); err != nil {
+		return Config{}, err
+	}
+	if err := intEnv(DefaultEnvVarFetchMaxAttempts, &cfg.FetchMaxAttempts); err != nil {
+		return Config{}, err
+	}
+	if err := durationEnv(DefaultEnvVarFetchBackoff, &cfg.FetchBackoff); err != nil {
+		return Config{}, err
+	}
+	if err := intEnv(DefaultEnvVarBreakerThreshold, &cfg.BreakerThreshold); err != nil {
+		return Config{}, err
+	}
+	if err := durationEnv(DefaultEnvVarBreakerCooldown, &cfg.BreakerCooldown); err != nil {
+		return Config{}, err
+	}
+
+	if err := durationEnv(DefaultEnvVarRetrainInterval, &cfg.RetrainInterval); err != nil {
+		return Config{}, err
+	}
+	cfg.RetrainCron = os.Getenv(DefaultEnvVarRetrainCron)
+	cfg.AdminToken = os.Getenv(DefaultEnvVarAdminToken)
+	if err := intEnv(DefaultEnvVarMaxBatchSize, &cfg.MaxBatchSize); err != nil {
+		return Config{}, err
+	}
+	if err := intEnv(DefaultEnvVarMaxBodyBytes, &cfg.MaxBodyBytes); err != nil {
+		return Config{}, err
+	}
+	cfg.OODPolicy = os.Getenv(DefaultEnvVarOODPolicy)
+	switch cfg.OODPolicy {
+	case "":
+		cfg.OODPolicy = OODWarn
+	case OODAllow, OODWarn, OODReject:
+	default:
+		return Config{}, fmt.Errorf("%s: unknown policy %q: want %s, %s or %s",
+			DefaultEnvVarOODPolicy, cfg.OODPolicy, OODAllow, OODWarn, OODReject)
+	}
+
+	cfg.Model = os.Getenv(DefaultEnvVarModel)
+	switch cfg.Model {
+	case "":
+		cfg.Model = ModelLinreg
+	case ModelLinreg:
+	case ModelRidge, ModelLasso:
+		if err := floatEnv(DefaultEnvVarModelLambda, &cfg.ModelLambda); err != nil {
+			return Config{}, err
+		}
+		if err := intEnv(DefaultEnvVarModelFolds, &cfg.ModelFolds); err != nil {
+			return Config{}, err
+		}
+	case ModelGBT:
+		for _, v := range []struct {
+			key string
+			dst *int
+		}{
+			{DefaultEnvVarModelSeed, &cfg.ModelSeed},
+			{DefaultEnvVarGBTTrees, &cfg.GBTTrees},
+			{DefaultEnvVarGBTMaxDepth, &cfg.GBTMaxDepth},
+		} {
+			if err := intEnv(v.key, v.dst); err != nil {
+				return Config{}, err
+			}
+		}
+		if err := floatEnv(DefaultEnvVarGBTLearningRate, &cfg.GBTLearningRate); err != nil {
+			return Config{}, err
+		}
+		// off or -1 disable early stopping, as a negative gbt.Options value does.
+		switch v := os.Getenv(DefaultEnvVarGBTEarlyStopping); v {
+		case "off", "-1":
+			cfg.GBTEarlyStoppingRounds = -1
+		default:
+			if err := intEnv(DefaultEnvVarGBTEarlyStopping, &cfg.GBTEarlyStoppingRounds); err != nil {
+				return Config{}, fmt.Errorf("%w, off or -1", err)
+			}
+		}
+	case ModelForest:
+		if err := intEnv(DefaultEnvVarModelSeed, &cfg.ModelSeed); err != nil {
+			return Config{}, err
+		}
+		if err := intEnv(DefaultEnvVarForestTrees, &cfg.ForestTrees); err != nil {
+			return Config{}, err
+		}
+	case ModelHoltWinters:
+		for _, v := range strings.Split(os.Getenv(DefaultEnvVarHolidays), ",") {
+			if v = strings.TrimSpace(v); v == "" {
+				continue
+			}
+			d, err := time.Parse(time.DateOnly, v)
+			if err != nil {
+				return Config{}, fmt.Errorf("%s: invalid date %q, want YYYY-MM-DD", DefaultEnvVarHolidays, v)
+			}
+			cfg.Holidays = append(cfg.Holidays, d)
+		}
+	default:
+		return Config{}, fmt.Errorf("%s: unknown model %q", DefaultEnvVarModel, cfg.Model)
+	}
+
+	cfg.Store = os.Getenv(DefaultEnvVarStore)
+	switch cfg.Store {
+	case "":
+		cfg.Store = StoreMemory
+	case StoreMemory:
+	case StoreSQLite:
+		cfg.SQLitePath = os.Getenv(DefaultEnvVarSQLitePath)
+		if cfg.SQLitePath == "" {
+			cfg.SQLitePath = DefaultSQLitePath
+		}
+	case StoreJSONL:
+		cfg.JSONLPath = os.Getenv(DefaultEnvVarJSONLPath)
+		if cfg.JSONLPath == "" {
+			cfg.JSONLPath = DefaultJSONLPath
+		}
+		if err := intEnv(DefaultEnvVarJSONLMax, &cfg.JSONLMaxSize); err != nil {
+			return Config{}, err
+		}
+	default:
+		return Config{}, fmt.Errorf("%s: unknown store %q", DefaultEnvVarStore, cfg.Store)
+	}
+
+	if v := os.Getenv(DefaultEnvVarMaxRejectRatio); v != "" {
+		r, err := strconv.ParseFloat(v, 64)
+		if err != nil || r < 0 || r > 1 {
+			return Config{}, fmt.Errorf("%s must be a number between 0 and 1", DefaultEnvVarMaxRejectRatio)
+		}
+		cfg.MaxRejectRatio = r
+	}
+
+	switch cfg.Source {
+	case SourceGSheets:
+		creds := os.Getenv(DefaultEnvVarCreds)
+		if creds == "" {
+			return Config{}, fmt.Errorf("%s is required", DefaultEnvVarCreds)
+		}
+		id := os.Getenv(DefaultEnvVarSheetID)
+		if id == "" {
+			return Config{}, fmt.Errorf("%s is required", DefaultEnvVarSheetID)
+		}
+		cfg.CredsJSON = []byte(creds)
+		cfg.SpreadsheetID = id
+		cfg.SheetName = os.Getenv(DefaultEnvVarSheetName)
+		cfg.SheetRange = os.Getenv(DefaultEnvVarSheetRange)
+	case SourceCSV:
+		cfg.CSVPath = os.Getenv(DefaultEnvVarCSVPath)
+		if cfg.CSVPath == "" {
+			return Config{}, fmt.Errorf("%s is required", DefaultEnvVarCSVPath)
+		}
+	default:
+		return Config{}, fmt.Errorf("%s: unknown source %q", DefaultEnvVarSource, cfg.Source)
+	}
+
+	return cfg, nil
+}
+
+// durationEnv overrides *dst with the duration in env var key, if set.
+func durationEnv(key string, dst *time.Duration) error {
+	v := os.Getenv(key)
+	if v == "" {
+		return nil
+	}
+	d, err := time.ParseDuration(v)
+	if err != nil || d < 0 {
+		return fmt.Errorf("%s must be a non-negative duration such as 5s", key)
+	}
+	*dst = d
+	return nil
+}
+
+// intEnv overrides *dst with the integer in env var key, if set.
+func intEnv(key string, dst *int) error {
+	v := os.Getenv(key)
+	if v == "" {
+		return nil
+	}
+	n, err := strconv.Atoi(v)
+	if err != nil || n < 0 {
+		return fmt.Errorf("%s must be a non-negative integer", key)
+	}
+	*dst = n
+	return nil
+}
+
+// floatEnv overrides *dst with the number in env var key, if set.
+func floatEnv(key string, dst *float64) error {
+	v := os.Getenv(key)
+	if v == "" {
+		return nil
+	}
+	f, err := strconv.ParseFloat(v, 64)
+	if err != nil || f < 0 || math.IsInf(f, 0) || math.IsNaN(f) {
+		return fmt.Errorf("%s must be a non-negative number", key)
+	}
+	*dst = f
+	return nil
 }
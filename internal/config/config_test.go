@@ -0,0 +1,33 @@
+package config
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+// setCSV configures the minimal environment Load accepts.
+func setCSV(t *testing.T) {
+	t.Setenv(DefaultEnvVarSource, SourceCSV)
+	t.Setenv(DefaultEnvVarCSVPath, "metrics.csv")
+}
+
+func TestLoad_OODPolicy(t *testing.T) {
+	setCSV(t)
+
+	cfg, err := Load()
+	require.NoError(t, err)
+	assert.Equal(t, OODWarn, cfg.OODPolicy, "default")
+
+	for _, p := range []string{OODAllow, OODWarn, OODReject} {
+		t.Setenv(DefaultEnvVarOODPolicy, p)
+		cfg, err := Load()
+		require.NoError(t, err)
+		assert.Equal(t, p, cfg.OODPolicy)
+	}
+
+	t.Setenv(DefaultEnvVarOODPolicy, "ignore")
+	_, err = Load()
+	assert.ErrorContains(t, err, DefaultEnvVarOODPolicy)
+}
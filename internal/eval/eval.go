@@ -0,0 +1,268 @@
+// Package eval backtests models on historical metrics.Daily rows with
+// k-fold cross-validation and walk-forward (time-ordered) evaluation.
+package eval
+
+import (
+	"errors"
+	"fmt"
+	"math"
+	"sort"
+
+	"github.com/thisiscetin/podpredict/internal/metrics"
+	"github.com/thisiscetin/podpredict/internal/model"
+)
+
+// Methods reported in Report.Method.
+const (
+	MethodKFold       = "kfold"
+	MethodWalkForward = "walk_forward"
+)
+
+// Metrics summarizes prediction errors for one tier over all evaluated days.
+type Metrics struct {
+	// MAE is the mean absolute error in pods.
+	MAE float64 `json:"mae"`
+	// RMSE is the root mean squared error in pods.
+	RMSE float64 `json:"rmse"`
+	// MAPE is the mean absolute percentage error, skipping days with zero actual pods.
+	MAPE float64 `json:"mape"`
+	// UnderProvisionedPct is the percentage of days predicted below the actual pods.
+	UnderProvisionedPct float64 `json:"under_provisioned_pct"`
+	// OverProvisionedPct is the percentage of days predicted above the actual pods.
+	OverProvisionedPct float64 `json:"over_provisioned_pct"`
+}
+
+// Report is the outcome of a backtest.
+type Report struct {
+	// Method is MethodKFold or MethodWalkForward.
+	Method string `json:"method"`
+	// Folds is the number of train/test splits evaluated.
+	Folds int `json:"folds"`
+	// Days is the number of out-of-sample predictions scored.
+	Days int     `json:"days"`
+	FE   Metrics `json:"fe"`
+	BE   Metrics `json:"be"`
+}
+
+// ErrForecaster is returned by KFold for models that implement
+// model.Forecaster. They model the date series itself, which a held-out day
+// leaves a gap in rather than being unseen, so only WalkForward applies.
+var ErrForecaster = errors.New("k-fold does not apply to models that forecast by date")
+
+// KFold trains k models, each on all rows but one fold, and scores them on
+// the held-out fold. Row i goes to fold i mod k, so the split is
+// deterministic and every fold spans the whole date range.
+// Only rows with both FE and BE pods are used.
+func KFold(newModel model.Factory, data []metrics.Daily, k int) (Report, error) {
+	if isForecaster(newModel) {
+		return Report{}, ErrForecaster
+	}
+	rows := withPods(data)
+	if k < 2 {
+		return Report{}, errors.New("k must be at least 2")
+	}
+	if len(rows) < k {
+		return Report{}, fmt.Errorf("need at least %d rows with pods for %d folds, got %d", k, k, len(rows))
+	}
+
+	var acc accumulator
+	for fold := 0; fold < k; fold++ {
+		var train, test []metrics.Daily
+		for i, d := range rows {
+			if i%k == fold {
+				test = append(test, d)
+			} else {
+				train = append(train, d)
+			}
+		}
+		if err := acc.score(newModel, train, test); err != nil {
+			return Report{}, fmt.Errorf("fold %d: %w", fold+1, err)
+		}
+	}
+	return acc.report(MethodKFold, k), nil
+}
+
+// WalkForward sorts rows by date, trains on the first minTrain rows and
+// scores the next horizon rows, then grows the training window by horizon
+// and repeats until the data is exhausted. Models never see the future, and
+// a model.Forecaster forecasts the scored rows by date.
+// Only rows with both FE and BE pods are used.
+func WalkForward(newModel model.Factory, data []metrics.Daily, minTrain, horizon int) (Report, error) {
+	rows := withPods(data)
+	sort.SliceStable(rows, func(a, b int) bool { return rows[a].Date.Before(rows[b].Date) })
+	if minTrain < 1 || horizon < 1 {
+		return Report{}, errors.New("minTrain and horizon must be positive")
+	}
+	if len(rows) <= minTrain {
+		return Report{}, fmt.Errorf("need more than %d rows with pods, got %d", minTrain, len(rows))
+	}
+
+	var acc accumulator
+	folds := 0
+	for start := minTrain; start < len(rows); start += horizon {
+		end := min(start+horizon, len(rows))
+		if err := acc.score(newModel, rows[:start], rows[start:end]); err != nil {
+			return Report{}, fmt.Errorf("window ending %s: %w", rows[start-1].Date.Format("2006-01-02"), err)
+		}
+		folds++
+	}
+	return acc.report(MethodWalkForward, folds), nil
+}
+
+// Score computes Metrics for paired predicted and actual pod counts.
+// It panics if the slices differ in length.
+func Score(pred, actual []float64) Metrics {
+	if len(pred) != len(actual) {
+		panic("eval: Score called with slices of different lengths")
+	}
+	var t tierAcc
+	for i := range pred {
+		t.add(pred[i], actual[i])
+	}
+	return t.metrics()
+}
+
+// isForecaster reports whether the models built by newModel implement
+// model.Forecaster.
+func isForecaster(newModel model.Factory) bool {
+	_, ok := newModel().(model.Forecaster)
+	return ok
+}
+
+// withPods returns a copy of the rows that have both FE and BE pods.
+func withPods(data []metrics.Daily) []metrics.Daily {
+	out := make([]metrics.Daily, 0, len(data))
+	for _, d := range data {
+		if d.HasPods() {
+			out = append(out, d)
+		}
+	}
+	return out
+}
+
+// accumulator collects out-of-sample errors across folds.
+type accumulator struct {
+	fe, be tierAcc
+}
+
+// score trains a fresh model on train and accumulates its errors on test,
+// scoring a model.Forecaster on the date of each test row.
+func (a *accumulator) score(newModel model.Factory, train, test []metrics.Daily) error {
+	m := newModel()
+	if err := m.Train(train); err != nil {
+		return err
+	}
+	for _, d := range test {
+		f := model.FeaturesFromDaily(d)
+		fe, be, err := model.PredictOn(m, d.Date, &f)
+		if err != nil {
+			return err
+		}
+		actualFE, actualBE, _ := d.Pods()
+		a.fe.add(float64(fe), float64(actualFE))
+		a.be.add(float64(be), float64(actualBE))
+	}
+	return nil
+}
+
+func (a *accumulator) report(method string, folds int) Report {
+	return Report{
+		Method: method,
+		Folds:  folds,
+		Days:   a.fe.n,
+		FE:     a.fe.metrics(),
+		BE:     a.be.metrics(),
+	}
+}
+
+// tierAcc accumulates error sums for a single tier.
+type tierAcc struct {
+	n, under, over int
+	absSum, sqSum  float64
+	pctSum         float64
+	pctN           int
+}
+
+func (t *tierAcc) add(pred, actual float64) {
+	diff := pred - actual
+	t.n++
+	t.absSum += math.Abs(diff)
+	t.sqSum += diff * diff
+	if actual != 0 {
+		t.pctSum += math.Abs(diff / actual)
+		t.pctN++
+	}
+	switch {
+	case diff < 0:
+		t.under++
+	case diff > 0:
+		t.over++
+	}
+}
+
+func (t *tierAcc) metrics() Metrics {
+	if t.n == 0 {
+		return Metrics{}
+	}
+	n := float64(t.n)
+	m := Metrics{
+		MAE:                 t.absSum / n,
+		RMSE:                math.Sqrt(t.sqSum / n),
+		UnderProvisionedPct: 100 * float64(t.under) / n,
+		OverProvisionedPct:  100 * float64(t.over) / n,
+	}
+	if t.pctN > 0 {
+		m.MAPE = 100 * t.pctSum / float64(t.pctN)
+	}
+	return m
+}
+
+// DefaultFolds is the number of folds used by Run when Options.Folds is 0.
+const DefaultFolds = 5
+
+// Options configures Run. Zero values select the defaults.
+type Options struct {
+	// Folds is k for KFold (default DefaultFolds).
+	Folds int
+	// MinTrain is the initial training window of WalkForward
+	// (default half of the rows with pods).
+	MinTrain int
+	// Horizon is the number of rows scored per WalkForward step (default 1).
+	Horizon int
+}
+
+// Result holds the reports of both backtests. KFold is nil for models that
+// implement model.Forecaster (see ErrForecaster).
+type Result struct {
+	KFold       *Report `json:"kfold,omitempty"`
+	WalkForward Report  `json:"walk_forward"`
+}
+
+// Run evaluates newModel on data with both KFold and WalkForward, or with
+// WalkForward only for a model.Forecaster.
+func Run(newModel model.Factory, data []metrics.Daily, opts Options) (Result, error) {
+	if opts.Folds == 0 {
+		opts.Folds = DefaultFolds
+	}
+	if opts.MinTrain == 0 {
+		opts.MinTrain = max(len(withPods(data))/2, 1)
+	}
+	if opts.Horizon == 0 {
+		opts.Horizon = 1
+	}
+
+	var res Result
+	if !isForecaster(newModel) {
+		kf, err := KFold(newModel, data, opts.Folds)
+		if err != nil {
+			return Result{}, fmt.Errorf("k-fold: %w", err)
+		}
+		res.KFold = &kf
+	}
+	wf, err := WalkForward(newModel, data, opts.MinTrain, opts.Horizon)
+	if err != nil {
+		return Result{}, fmt.Errorf("walk-forward: %w", err)
+	}
+	res.WalkForward = wf
+	return res, nil
+}
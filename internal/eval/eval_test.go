@@ -0,0 +1,183 @@
+package eval
+
+import (
+	"errors"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"github.com/thisiscetin/podpredict/internal/metrics"
+	"github.com/thisiscetin/podpredict/internal/model"
+	"github.com/thisiscetin/podpredict/internal/model/linreg"
+)
+
+// constModel always predicts fixed pods and records its training rows.
+type constModel struct {
+	fe, be  int
+	trained *[][]metrics.Daily
+	err     error
+}
+
+func (m *constModel) Train(rows []metrics.Daily) error {
+	if m.trained != nil {
+		*m.trained = append(*m.trained, rows)
+	}
+	return m.err
+}
+
+func (m *constModel) Predict(_ *model.Features) (model.FEPods, model.BEPods, error) {
+	return model.FEPods(m.fe), model.BEPods(m.be), nil
+}
+
+// dateModel is a constModel that also implements model.Forecaster,
+// forecasting the day of the month as pods.
+type dateModel struct{ constModel }
+
+func (m *dateModel) Forecast(date time.Time) (model.FEPods, model.BEPods, error) {
+	return model.FEPods(date.Day()), model.BEPods(date.Day()), nil
+}
+
+func makeDays(pods ...int) []metrics.Daily {
+	base := time.Date(2025, 1, 1, 0, 0, 0, 0, time.UTC)
+	out := make([]metrics.Daily, len(pods))
+	for i, p := range pods {
+		fe, be := p, p
+		d, err := metrics.NewDaily(base.AddDate(0, 0, i), float64(i+1), i+1, 1, &fe, &be)
+		if err != nil {
+			panic(err)
+		}
+		out[i] = d
+	}
+	return out
+}
+
+func TestKFold_Metrics(t *testing.T) {
+	// Predict 4 against actuals 2, 4, 5, 8: errors +2, 0, -1, -4.
+	data := makeDays(2, 4, 5, 8)
+	r, err := KFold(func() model.Model { return &constModel{fe: 4, be: 4} }, data, 2)
+	require.NoError(t, err)
+
+	assert.Equal(t, MethodKFold, r.Method)
+	assert.Equal(t, 2, r.Folds)
+	assert.Equal(t, 4, r.Days)
+	assert.InDelta(t, 7.0/4, r.FE.MAE, 1e-9)
+	assert.InDelta(t, 2.2913, r.FE.RMSE, 1e-4) // sqrt((4+0+1+16)/4)
+	assert.InDelta(t, 100*(1+0+0.2+0.5)/4, r.FE.MAPE, 1e-9)
+	assert.InDelta(t, 50, r.FE.UnderProvisionedPct, 1e-9)
+	assert.InDelta(t, 25, r.FE.OverProvisionedPct, 1e-9)
+	assert.Equal(t, r.FE, r.BE)
+}
+
+func TestKFold_HoldsOutEachRowOnce(t *testing.T) {
+	var trained [][]metrics.Daily
+	data := makeDays(1, 2, 3, 4, 5, 6)
+	_, err := KFold(func() model.Model { return &constModel{fe: 1, be: 1, trained: &trained} }, data, 3)
+	require.NoError(t, err)
+
+	require.Len(t, trained, 3)
+	for _, rows := range trained {
+		assert.Len(t, rows, 4)
+	}
+}
+
+func TestKFold_InvalidK(t *testing.T) {
+	f := func() model.Model { return &constModel{} }
+	_, err := KFold(f, makeDays(1, 2, 3), 1)
+	assert.Error(t, err)
+	_, err = KFold(f, makeDays(1, 2, 3), 4)
+	assert.Error(t, err)
+}
+
+func TestKFold_TrainError(t *testing.T) {
+	boom := errors.New("boom")
+	_, err := KFold(func() model.Model { return &constModel{err: boom} }, makeDays(1, 2, 3, 4), 2)
+	assert.ErrorIs(t, err, boom)
+}
+
+func TestWalkForward_NeverTrainsOnTheFuture(t *testing.T) {
+	var trained [][]metrics.Daily
+	data := makeDays(1, 2, 3, 4, 5, 6, 7)
+	// Shuffle input order; WalkForward must sort by date.
+	data[0], data[6] = data[6], data[0]
+
+	r, err := WalkForward(func() model.Model { return &constModel{fe: 1, be: 1, trained: &trained} }, data, 3, 2)
+	require.NoError(t, err)
+
+	assert.Equal(t, MethodWalkForward, r.Method)
+	assert.Equal(t, 2, r.Folds) // test windows [3,5) and [5,7)
+	assert.Equal(t, 4, r.Days)
+	require.Len(t, trained, 2)
+	assert.Len(t, trained[0], 3)
+	assert.Len(t, trained[1], 5)
+	for i := 1; i < len(trained[1]); i++ {
+		assert.True(t, trained[1][i-1].Date.Before(trained[1][i].Date))
+	}
+}
+
+func TestWalkForward_NotEnoughRows(t *testing.T) {
+	_, err := WalkForward(func() model.Model { return &constModel{} }, makeDays(1, 2), 2, 1)
+	assert.Error(t, err)
+}
+
+func TestKFold_Linreg(t *testing.T) {
+	base := time.Date(2025, 1, 1, 0, 0, 0, 0, time.UTC)
+	var data []metrics.Daily
+	for i := 0; i < 20; i++ {
+		gmv, users, mc := float64(100+10*i), 10+i%7, float64(5+i%3)
+		fe := int(5 + 0.5*gmv + 2*float64(users) + 3*mc)
+		be := int(-2 + 0.2*gmv + float64(users) + 4*mc)
+		d, err := metrics.NewDaily(base.AddDate(0, 0, i), gmv, users, mc, &fe, &be)
+		require.NoError(t, err)
+		data = append(data, d)
+	}
+
+	r, err := KFold(linreg.NewModel, data, 5)
+	require.NoError(t, err)
+	assert.Equal(t, 20, r.Days)
+	assert.Less(t, r.FE.MAE, 1.0, "near-perfect linear data should be predicted within a pod")
+	assert.Less(t, r.BE.MAE, 1.0)
+}
+
+func TestRun_Defaults(t *testing.T) {
+	data := makeDays(1, 2, 3, 4, 5, 6, 7, 8, 9, 10)
+	res, err := Run(func() model.Model { return &constModel{fe: 5, be: 5} }, data, Options{})
+	require.NoError(t, err)
+
+	require.NotNil(t, res.KFold)
+	assert.Equal(t, DefaultFolds, res.KFold.Folds)
+	assert.Equal(t, 10, res.KFold.Days)
+	assert.Equal(t, 5, res.WalkForward.Folds) // MinTrain 5, Horizon 1
+	assert.Equal(t, 5, res.WalkForward.Days)
+}
+
+func TestRun_PropagatesErrors(t *testing.T) {
+	_, err := Run(func() model.Model { return &constModel{} }, makeDays(1, 2), Options{Folds: 3})
+	assert.ErrorContains(t, err, "k-fold")
+}
+
+func TestScore(t *testing.T) {
+	m := Score([]float64{4, 4, 4, 4}, []float64{2, 4, 5, 8})
+	assert.InDelta(t, 7.0/4, m.MAE, 1e-9)
+	assert.InDelta(t, 50, m.UnderProvisionedPct, 1e-9)
+	assert.InDelta(t, 25, m.OverProvisionedPct, 1e-9)
+
+	assert.Equal(t, Metrics{}, Score(nil, nil))
+}
+
+func TestRun_ForecasterIsBacktestedByDate(t *testing.T) {
+	// Pods equal the day of the month, which only Forecast gets right.
+	data := makeDays(1, 2, 3, 4, 5, 6, 7, 8, 9, 10)
+	f := func() model.Model { return &dateModel{constModel{fe: 100, be: 100}} }
+
+	_, err := KFold(f, data, 2)
+	assert.ErrorIs(t, err, ErrForecaster)
+
+	res, err := Run(f, data, Options{})
+	require.NoError(t, err)
+	assert.Nil(t, res.KFold)
+	assert.Equal(t, 5, res.WalkForward.Days)
+	assert.Zero(t, res.WalkForward.FE.MAE)
+	assert.Zero(t, res.WalkForward.BE.MAE)
+}
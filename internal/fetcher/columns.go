@@ -0,0 +1,96 @@
+package fetcher
+
+import (
+	"fmt"
+	"strings"
+)
+
+// DateLayout is the default layout of the Date column (dd/mm/yyyy).
+const DateLayout = "02/01/2006"
+
+// Field identifies a metrics.Daily field that is read from a table column.
+type Field string
+
+const (
+	FieldDate          Field = "date"
+	FieldGMV           Field = "gmv"
+	FieldUsers         Field = "users"
+	FieldMarketingCost Field = "marketing_cost"
+	FieldFEPods        Field = "fe_pods"
+	FieldBEPods        Field = "be_pods"
+)
+
+// fields lists every known Field in canonical order.
+var fields = []Field{FieldDate, FieldGMV, FieldUsers, FieldMarketingCost, FieldFEPods, FieldBEPods}
+
+// required reports whether a column must be present in the header.
+// Pod columns are optional so that KPI-only tables can still be read.
+func (f Field) required() bool {
+	return f != FieldFEPods && f != FieldBEPods
+}
+
+// Columns maps each Field to the header name of the column that holds it.
+type Columns map[Field]string
+
+// DefaultColumns returns the mapping for the canonical
+// Date/GMV/Users/MarketingCost/FEPods/BEPods header.
+func DefaultColumns() Columns {
+	return Columns{
+		FieldDate:          "Date",
+		FieldGMV:           "GMV",
+		FieldUsers:         "Users",
+		FieldMarketingCost: "MarketingCost",
+		FieldFEPods:        "FEPods",
+		FieldBEPods:        "BEPods",
+	}
+}
+
+// ParseColumns parses a mapping such as "date=Day,gmv=Revenue" on top of
+// DefaultColumns. Fields that are not mentioned keep their default header.
+// An empty string yields DefaultColumns.
+func ParseColumns(s string) (Columns, error) {
+	cols := DefaultColumns()
+	for _, pair := range strings.Split(s, ",") {
+		pair = strings.TrimSpace(pair)
+		if pair == "" {
+			continue
+		}
+		k, v, ok := strings.Cut(pair, "=")
+		if !ok {
+			return nil, fmt.Errorf("invalid column mapping %q: want field=header", pair)
+		}
+		f := Field(strings.ToLower(strings.TrimSpace(k)))
+		if _, known := cols[f]; !known {
+			return nil, fmt.Errorf("invalid column mapping %q: unknown field %q", pair, f)
+		}
+		header := strings.TrimSpace(v)
+		if header == "" {
+			return nil, fmt.Errorf("invalid column mapping %q: empty header", pair)
+		}
+		cols[f] = header
+	}
+	return cols, nil
+}
+
+// Layout describes how a tabular source maps onto metrics.Daily.
+// The zero value uses DateLayout and DefaultColumns.
+type Layout struct {
+	// DateLayout is the time.Parse layout of the date column.
+	DateLayout string
+	// Columns maps fields to header names. Missing fields use their default header.
+	Columns Columns
+}
+
+// withDefaults fills unset values from DateLayout and DefaultColumns.
+func (l Layout) withDefaults() Layout {
+	out := Layout{DateLayout: l.DateLayout, Columns: DefaultColumns()}
+	if out.DateLayout == "" {
+		out.DateLayout = DateLayout
+	}
+	for f, h := range l.Columns {
+		if h != "" {
+			out.Columns[f] = h
+		}
+	}
+	return out
+}
@@ -0,0 +1,58 @@
+package fetcher
+
+import (
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestParseColumns_Empty(t *testing.T) {
+	cols, err := ParseColumns("")
+	require.NoError(t, err)
+	assert.Equal(t, DefaultColumns(), cols)
+}
+
+func TestParseColumns_Overrides(t *testing.T) {
+	cols, err := ParseColumns(" date = Day , GMV=Revenue ")
+	require.NoError(t, err)
+	assert.Equal(t, "Day", cols[FieldDate])
+	assert.Equal(t, "Revenue", cols[FieldGMV])
+	assert.Equal(t, "Users", cols[FieldUsers])
+}
+
+func TestParseColumns_Invalid(t *testing.T) {
+	for _, s := range []string{"date", "weekday=Day", "gmv="} {
+		_, err := ParseColumns(s)
+		assert.Error(t, err, s)
+	}
+}
+
+func TestNewSchema_CaseInsensitiveHeaders(t *testing.T) {
+	s, err := NewSchema([]string{" date ", "gmv", "USERS", "marketingcost"}, Layout{})
+	require.NoError(t, err)
+
+	d, _, err := s.ParseRow([]string{"02/01/2025", "10", "2", "3"}, 2)
+	require.NoError(t, err)
+	assert.Equal(t, time.Date(2025, 1, 2, 0, 0, 0, 0, time.UTC), d.Date)
+	assert.Nil(t, d.FEPods)
+}
+
+func TestNewSchema_AmbiguousHeader(t *testing.T) {
+	_, err := NewSchema([]string{"Date", "GMV", "GMV", "Users", "MarketingCost"}, Layout{})
+	assert.Error(t, err)
+}
+
+func TestNewSchema_UnmappedDuplicateIsIgnored(t *testing.T) {
+	_, err := NewSchema([]string{"Date", "GMV", "Users", "MarketingCost", "Notes", "Notes"}, Layout{})
+	assert.NoError(t, err)
+}
+
+func TestSchema_ParseRow_MissingRequiredCell(t *testing.T) {
+	s, err := NewSchema([]string{"Date", "GMV", "Users", "MarketingCost"}, Layout{})
+	require.NoError(t, err)
+
+	_, _, err = s.ParseRow([]string{"02/01/2025", "10", "2"}, 2)
+	assert.Error(t, err)
+}
@@ -0,0 +1,85 @@
+package csv
+
+import (
+	"context"
+	stdcsv "encoding/csv"
+	"errors"
+	"fmt"
+	"io"
+	"os"
+
+	"github.com/thisiscetin/podpredict/internal/fetcher"
+	"github.com/thisiscetin/podpredict/internal/metrics"
+)
+
+// impl implements the fetcher.Fetcher interface for a local CSV file.
+type impl struct {
+	path   string
+	layout fetcher.Layout
+}
+
+// NewFetcher creates a new CSV fetcher reading from path.
+// The first line must be a header; columns are located by the header names
+// in layout, exactly as for the Google Sheets fetcher.
+func NewFetcher(path string, layout fetcher.Layout) (fetcher.Fetcher, error) {
+	if path == "" {
+		return nil, errors.New("empty csv path")
+	}
+	if _, err := os.Stat(path); err != nil {
+		return nil, fmt.Errorf("failed to stat csv file: %w", err)
+	}
+	return &impl{path: path, layout: layout}, nil
+}
+
+// Fetch reads the CSV file and converts it into a slice of metrics.Daily.
+// Rows that fail to parse are skipped and recorded in the returned fetcher.Report.
+func (i *impl) Fetch(ctx context.Context) ([]metrics.Daily, fetcher.Report, error) {
+	if err := ctx.Err(); err != nil {
+		return nil, fetcher.Report{}, err
+	}
+	f, err := os.Open(i.path)
+	if err != nil {
+		return nil, fetcher.Report{}, fmt.Errorf("failed to open csv file: %w", err)
+	}
+	defer f.Close()
+
+	return i.read(ctx, f)
+}
+
+// read parses CSV records from r, resolving columns from the header line.
+// It stops early if ctx is cancelled.
+func (i *impl) read(ctx context.Context, r io.Reader) ([]metrics.Daily, fetcher.Report, error) {
+	cr := stdcsv.NewReader(r)
+	cr.FieldsPerRecord = -1 // trailing pod columns are optional
+	cr.TrimLeadingSpace = true
+
+	header, err := cr.Read()
+	if errors.Is(err, io.EOF) {
+		return nil, fetcher.Report{}, errors.New("csv file is empty: header row not found")
+	}
+	if err != nil {
+		return nil, fetcher.Report{}, fmt.Errorf("failed to read csv header: %w", err)
+	}
+	schema, err := fetcher.NewSchema(header, i.layout)
+	if err != nil {
+		return nil, fetcher.Report{}, fmt.Errorf("invalid csv header: %w", err)
+	}
+
+	in := fetcher.NewIngest("csv:"+i.path, schema)
+	for rowNum := 2; ; rowNum++ {
+		if err := ctx.Err(); err != nil {
+			return nil, fetcher.Report{}, err
+		}
+		row, err := cr.Read()
+		if errors.Is(err, io.EOF) {
+			break
+		}
+		if err != nil {
+			return nil, fetcher.Report{}, fmt.Errorf("failed to read csv data: %w", err)
+		}
+		in.Add(row, rowNum)
+	}
+
+	results, report := in.Result()
+	return results, report, nil
+}
@@ -0,0 +1,123 @@
+package csv
+
+import (
+	"context"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"github.com/thisiscetin/podpredict/internal/fetcher"
+)
+
+// writeCSV writes content to a temp file and returns its path.
+func writeCSV(t *testing.T, content string) string {
+	t.Helper()
+	p := filepath.Join(t.TempDir(), "metrics.csv")
+	require.NoError(t, os.WriteFile(p, []byte(content), 0o600))
+	return p
+}
+
+func TestNewFetcher_EmptyPath(t *testing.T) {
+	_, err := NewFetcher("", fetcher.Layout{})
+	assert.Error(t, err)
+}
+
+func TestNewFetcher_MissingFile(t *testing.T) {
+	_, err := NewFetcher(filepath.Join(t.TempDir(), "nope.csv"), fetcher.Layout{})
+	assert.Error(t, err)
+}
+
+func TestFetch_ValidData(t *testing.T) {
+	p := writeCSV(t, `Date,GMV,Users,MarketingCost,FEPods,BEPods
+22/12/2024,"13,224,723.00",123,"456,789.50",10,5
+23/12/2024,1000,10,50,,
+`)
+	f, err := NewFetcher(p, fetcher.Layout{})
+	require.NoError(t, err)
+
+	rows, _, err := f.Fetch(context.Background())
+	require.NoError(t, err)
+	require.Len(t, rows, 2)
+
+	assert.Equal(t, time.Date(2024, 12, 22, 0, 0, 0, 0, time.UTC), rows[0].Date)
+	assert.Equal(t, 13224723.00, rows[0].GMV)
+	assert.Equal(t, 123.0, rows[0].Users)
+	assert.Equal(t, 456789.50, rows[0].MarketingCost)
+	require.True(t, rows[0].HasPods())
+	assert.Equal(t, 10, *rows[0].FEPods)
+	assert.Equal(t, 5, *rows[0].BEPods)
+
+	assert.False(t, rows[1].HasFePods())
+	assert.False(t, rows[1].HasBePods())
+}
+
+func TestFetch_ShortRowsWithoutPods(t *testing.T) {
+	i := &impl{}
+	rows, _, err := i.read(context.Background(), strings.NewReader("Date,GMV,Users,MarketingCost\n01/01/2025,1000,1,50\n"))
+	require.NoError(t, err)
+	require.Len(t, rows, 1)
+	assert.Nil(t, rows[0].FEPods)
+}
+
+func TestFetch_SkipsInvalidRows(t *testing.T) {
+	i := &impl{}
+	rows, rep, err := i.read(context.Background(), strings.NewReader(`Date,GMV,Users,MarketingCost,FEPods,BEPods
+invalid-date,1000,1,50,1,1
+01/01/2025,abc,1,50,1,1
+02/01/2025,1000,1,50,1,1
+`))
+	require.NoError(t, err)
+	require.Len(t, rows, 1)
+	assert.Equal(t, time.Date(2025, 1, 2, 0, 0, 0, 0, time.UTC), rows[0].Date)
+	assert.Equal(t, 3, rep.RowsRead)
+	require.Len(t, rep.Rejected, 2)
+	assert.Equal(t, 2, rep.Rejected[0].Row)
+	assert.Equal(t, 3, rep.Rejected[1].Row)
+}
+
+func TestFetch_MalformedCSV(t *testing.T) {
+	i := &impl{}
+	_, _, err := i.read(context.Background(), strings.NewReader("Date,GMV,Users,MarketingCost\n\"unterminated,1\n"))
+	assert.Error(t, err)
+}
+
+func TestFetch_HeaderDrivenColumns(t *testing.T) {
+	i := &impl{layout: fetcher.Layout{Columns: fetcher.Columns{fetcher.FieldUsers: "Active Users"}}}
+	rows, _, err := i.read(context.Background(), strings.NewReader(`BEPods,Active Users,Date,Region,MarketingCost,GMV,FEPods
+5,12,01/01/2025,EU,50,1000,10
+`))
+	require.NoError(t, err)
+	require.Len(t, rows, 1)
+	assert.Equal(t, 12.0, rows[0].Users)
+	assert.Equal(t, 1000.0, rows[0].GMV)
+	assert.Equal(t, 10, *rows[0].FEPods)
+	assert.Equal(t, 5, *rows[0].BEPods)
+}
+
+func TestFetch_MissingHeaderColumn(t *testing.T) {
+	i := &impl{}
+	_, _, err := i.read(context.Background(), strings.NewReader("Date,GMV,MarketingCost\n01/01/2025,1000,50\n"))
+	assert.Error(t, err)
+}
+
+func TestFetch_EmptyFile(t *testing.T) {
+	i := &impl{}
+	_, _, err := i.read(context.Background(), strings.NewReader(""))
+	assert.Error(t, err)
+}
+
+func TestFetch_CancelledContext(t *testing.T) {
+	p := writeCSV(t, "Date,GMV,Users,MarketingCost\n01/01/2025,1000,1,50\n")
+	f, err := NewFetcher(p, fetcher.Layout{})
+	require.NoError(t, err)
+
+	ctx, cancel := context.WithCancel(context.Background())
+	cancel()
+
+	_, _, err = f.Fetch(ctx)
+	assert.ErrorIs(t, err, context.Canceled)
+}
@@ -0,0 +1,41 @@
+package fetcher
+
+import (
+	"context"
+	"errors"
+	"net"
+)
+
+// transientError marks an error as temporary, so retrying the Fetch may succeed.
+type transientError struct {
+	err error
+}
+
+func (e transientError) Error() string { return e.err.Error() }
+func (e transientError) Unwrap() error { return e.err }
+
+// Transient wraps err to signal that the failure is temporary, e.g. a rate
+// limit or an upstream 5xx. Transient(nil) returns nil.
+func Transient(err error) error {
+	if err == nil {
+		return nil
+	}
+	return transientError{err: err}
+}
+
+// IsTransient reports whether err is worth retrying: it was marked with
+// Transient, is a network timeout, or is a per-attempt deadline.
+func IsTransient(err error) bool {
+	if err == nil {
+		return false
+	}
+	var te transientError
+	if errors.As(err, &te) {
+		return true
+	}
+	if errors.Is(err, context.DeadlineExceeded) {
+		return true
+	}
+	var ne net.Error
+	return errors.As(err, &ne) && ne.Timeout()
+}
@@ -0,0 +1,23 @@
+package fetcher
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestIsTransient(t *testing.T) {
+	base := errors.New("rate limited")
+
+	assert.True(t, IsTransient(Transient(base)))
+	assert.True(t, IsTransient(fmt.Errorf("fetch: %w", Transient(base))))
+	assert.True(t, IsTransient(fmt.Errorf("fetch: %w", context.DeadlineExceeded)))
+	assert.False(t, IsTransient(base))
+	assert.False(t, IsTransient(context.Canceled))
+	assert.False(t, IsTransient(nil))
+	assert.Nil(t, Transient(nil))
+	assert.ErrorIs(t, Transient(base), base)
+}
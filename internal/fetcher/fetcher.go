@@ -1,6 +1,8 @@
 package fetcher
 
 import (
+	"context"
+
 	"github.com/thisiscetin/podpredict/internal/metrics"
 )
 
@@ -10,12 +12,21 @@ import (
 // Implementations should ensure deterministic, repeatable results where possible.
 // Example implementations include:
 //   - A Google Sheets fetcher that reads data from a spreadsheet range.
+//   - A CSV fetcher that reads the same layout from a local file.
 //   - A mock fetcher used in tests that returns static data.
+//
+// Cross-cutting concerns such as timeouts, retries and circuit breaking are
+// added by wrapping a Fetcher, see package resilient.
 type Fetcher interface {
 	// Fetch retrieves a complete set of daily metric records.
 	// The returned slice should contain one metrics.Daily value per day
 	// with any necessary validation already performed by the implementation.
+	// Rows that fail validation are skipped and described in the returned
+	// Report rather than logged, so callers can decide whether the data is
+	// good enough to train on.
 	// If the data source cannot be reached or the data cannot be parsed,
-	// an error is returned.
-	Fetch() ([]metrics.Daily, error)
+	// an error is returned. Temporary failures should be wrapped with
+	// Transient so that callers may retry them.
+	// Implementations must honour cancellation and deadlines on ctx.
+	Fetch(ctx context.Context) ([]metrics.Daily, Report, error)
 }
@@ -2,33 +2,61 @@ package gsheets
 
 import (
 	"context"
+	"errors"
 	"fmt"
-	"log"
+	"net/http"
 	"strconv"
 	"strings"
-	"time"
 
 	"github.com/thisiscetin/podpredict/internal/fetcher"
 	"github.com/thisiscetin/podpredict/internal/metrics"
 	"golang.org/x/oauth2/google"
+	"google.golang.org/api/googleapi"
 	"google.golang.org/api/option"
 	"google.golang.org/api/sheets/v4"
 )
 
 const (
-	readRange  = "Sheet1!A:F"
-	dateLayout = "02/01/2006" // dd/mm/yyyy
+	DefaultSheetName = "Sheet1"
+	DefaultRange     = "A:Z"
 )
 
+// Options configures where the metrics live inside the spreadsheet.
+// The zero value reads columns A:Z of "Sheet1" with the default layout.
+type Options struct {
+	// SheetName is the tab to read from.
+	SheetName string
+	// Range is an A1 range within the tab, e.g. "A:F" or "B2:K".
+	// Its first row must hold the column headers.
+	Range string
+	// Layout maps header names to fields and sets the date layout.
+	Layout fetcher.Layout
+}
+
+// readRange returns the A1 notation for the configured sheet and range.
+func (o Options) readRange() string {
+	name := o.SheetName
+	if name == "" {
+		name = DefaultSheetName
+	}
+	rng := o.Range
+	if rng == "" {
+		rng = DefaultRange
+	}
+	// Quote the tab name so names with spaces or punctuation work.
+	return fmt.Sprintf("'%s'!%s", strings.ReplaceAll(name, "'", "''"), rng)
+}
+
 // impl implements the fetcher.Fetcher interface for Google Sheets.
 type impl struct {
 	client        *sheets.Service
 	spreadsheetID string
+	opts          Options
 }
 
 // NewFetcher creates a new Google Sheets fetcher using service account credentials.
 // jsonCreds should contain the raw JSON of the service account key.
-func NewFetcher(ctx context.Context, jsonCreds []byte, spreadsheetID string) (fetcher.Fetcher, error) {
+func NewFetcher(ctx context.Context, jsonCreds []byte, spreadsheetID string, opts Options) (fetcher.Fetcher, error) {
 	config, err := google.JWTConfigFromJSON(jsonCreds, sheets.SpreadsheetsReadonlyScope)
 	if err != nil {
 		return nil, fmt.Errorf("failed to parse credentials: %w", err)
@@ -43,128 +71,83 @@ func NewFetcher(ctx context.Context, jsonCreds []byte, spreadsheetID string) (fe
 	return &impl{
 		client:        srv,
 		spreadsheetID: spreadsheetID,
+		opts:          opts,
 	}, nil
 }
 
 // Fetch retrieves metrics from the Google Sheet and converts them into a slice of metrics.Daily.
-// It logs errors per row but continues processing other rows.
-func (i *impl) Fetch() ([]metrics.Daily, error) {
-	resp, err := i.client.Spreadsheets.Values.Get(i.spreadsheetID, readRange).Do()
+// Columns are located by header name, so the sheet may be reordered or contain extra columns.
+// Rows that fail to parse are skipped and recorded in the returned fetcher.Report.
+// Rate limits and server-side errors are marked with fetcher.Transient.
+func (i *impl) Fetch(ctx context.Context) ([]metrics.Daily, fetcher.Report, error) {
+	resp, err := i.client.Spreadsheets.Values.Get(i.spreadsheetID, i.opts.readRange()).Context(ctx).Do()
 	if err != nil {
-		return nil, fmt.Errorf("failed to fetch sheet data: %w", err)
-	}
-
-	var results []metrics.Daily
-	for rowIdx, row := range resp.Values {
-		if rowIdx == 0 {
-			continue // skip header
-		}
-
-		daily, err := i.parseRow(row, rowIdx+1)
-		if err != nil {
-			log.Println(err)
-			continue
+		err = fmt.Errorf("failed to fetch sheet data: %w", err)
+		if isTransient(err) {
+			err = fetcher.Transient(err)
 		}
-		results = append(results, daily)
+		return nil, fetcher.Report{}, err
 	}
-
-	return results, nil
+	rng := resp.Range
+	if rng == "" {
+		rng = i.opts.readRange()
+	}
+	return i.parseValues(resp.Values, startRow(rng))
 }
 
-// parseRow parses a single row from the sheet into metrics.Daily.
-func (i *impl) parseRow(row []any, rowNum int) (metrics.Daily, error) {
-	var err error
-
-	if len(row) < 4 {
-		return metrics.Daily{}, fmt.Errorf("row %d: not enough columns", rowNum)
+// isTransient reports whether a Sheets API error is worth retrying.
+func isTransient(err error) bool {
+	var gerr *googleapi.Error
+	if errors.As(err, &gerr) {
+		return gerr.Code == http.StatusTooManyRequests || gerr.Code >= http.StatusInternalServerError
 	}
+	return false
+}
 
-	// Date
-	dateStr, ok := row[0].(string)
-	if !ok {
-		return metrics.Daily{}, fmt.Errorf("row %d: invalid date format: %v", rowNum, row[0])
+// startRow returns the sheet row number of the first row of an A1 range
+// such as "'Sheet1'!B5:K" (5), or 1 when the range starts with a whole
+// column, as in "A:Z".
+func startRow(a1 string) int {
+	if i := strings.LastIndex(a1, "!"); i >= 0 {
+		a1 = a1[i+1:]
 	}
-	date, err := parseDate(dateStr)
-	if err != nil {
-		return metrics.Daily{}, fmt.Errorf("row %d: failed to parse date: %v", rowNum, err)
-	}
-
-	// GMV
-	gmvStr, ok := row[1].(string)
-	if !ok {
-		return metrics.Daily{}, fmt.Errorf("row %d: invalid GMV format: %v", rowNum, row[1])
-	}
-	gmv, err := parseFloat(gmvStr)
-	if err != nil {
-		return metrics.Daily{}, fmt.Errorf("row %d: failed to parse GMV: %v", rowNum, err)
+	start, _, _ := strings.Cut(a1, ":")
+	n, err := strconv.Atoi(strings.TrimLeft(start, "$ABCDEFGHIJKLMNOPQRSTUVWXYZabcdefghijklmnopqrstuvwxyz"))
+	if err != nil || n < 1 {
+		return 1
 	}
+	return n
+}
 
-	// Users
-	usersStr, ok := row[2].(string)
-	if !ok {
-		return metrics.Daily{}, fmt.Errorf("row %d: invalid Users format: %v", rowNum, row[2])
+// parseValues resolves the header row, found on sheet row first, and
+// parses the remaining rows, reporting them by their sheet row number.
+func (i *impl) parseValues(values [][]any, first int) ([]metrics.Daily, fetcher.Report, error) {
+	if len(values) == 0 {
+		return nil, fetcher.Report{}, errors.New("sheet range is empty: header row not found")
 	}
-	users, err := parseInt(usersStr)
+	schema, err := fetcher.NewSchema(cells(values[0]), i.opts.Layout)
 	if err != nil {
-		return metrics.Daily{}, fmt.Errorf("row %d: failed to parse Users: %v", rowNum, err)
+		return nil, fetcher.Report{}, fmt.Errorf("invalid sheet header: %w", err)
 	}
 
-	// Marketing Cost
-	marketingStr, ok := row[3].(string)
-	if !ok {
-		return metrics.Daily{}, fmt.Errorf("row %d: invalid Marketing Cost format: %v", rowNum, row[3])
-	}
-	marketingCost, err := parseFloat(marketingStr)
-	if err != nil {
-		return metrics.Daily{}, fmt.Errorf("row %d: failed to parse Marketing Cost: %v", rowNum, err)
+	in := fetcher.NewIngest("gsheets:"+i.opts.readRange(), schema)
+	for rowIdx, row := range values[1:] {
+		in.Add(cells(row), first+1+rowIdx)
 	}
 
-	// FE Pods (optional)
-	var fePods *int
-	if len(row) > 4 && row[4] != "" {
-		if s, ok := row[4].(string); ok {
-			v, err := parseInt(s)
-			if err == nil {
-				fePods = &v
-			} else {
-				log.Printf("row %d: failed to parse FE Pods: %v", rowNum, err)
-			}
-		}
-	}
+	results, report := in.Result()
+	return results, report, nil
+}
 
-	// BE Pods (optional)
-	var bePods *int
-	if len(row) > 5 && row[5] != "" {
-		if s, ok := row[5].(string); ok {
-			v, err := parseInt(s)
-			if err == nil {
-				bePods = &v
-			} else {
-				log.Printf("row %d: failed to parse BE Pods: %v", rowNum, err)
-			}
+// cells converts a row of sheet values into strings.
+func cells(row []any) []string {
+	out := make([]string, len(row))
+	for idx, v := range row {
+		s, ok := v.(string)
+		if !ok {
+			s = fmt.Sprint(v)
 		}
+		out[idx] = s
 	}
-
-	dailyMetric, err := metrics.NewDaily(date, gmv, users, marketingCost, fePods, bePods)
-	if err != nil {
-		return metrics.Daily{}, fmt.Errorf("row %d: failed to create Daily metric: %v", rowNum, err)
-	}
-
-	return dailyMetric, nil
-}
-
-// parseFloat cleans a string of commas and parses it as float64.
-func parseFloat(s string) (float64, error) {
-	clean := strings.ReplaceAll(s, ",", "")
-	return strconv.ParseFloat(clean, 64)
-}
-
-// parseInt parses a string as int.
-func parseInt(s string) (int, error) {
-	return strconv.Atoi(s)
-}
-
-// parseDate parses a date string in dd/mm/yyyy format.
-func parseDate(s string) (time.Time, error) {
-	return time.Parse(dateLayout, s)
+	return out
 }
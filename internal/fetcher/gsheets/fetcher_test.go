@@ -1,15 +1,28 @@
 package gsheets
 
 import (
+	"errors"
+	"fmt"
 	"testing"
 	"time"
 
 	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"github.com/thisiscetin/podpredict/internal/fetcher"
+	"google.golang.org/api/googleapi"
 )
 
-func TestParseRow_ValidData(t *testing.T) {
-	i := &impl{}
+var defaultHeader = []any{"Date", "GMV", "Users", "MarketingCost", "FEPods", "BEPods"}
+
+// defaultSchema resolves the canonical header with the default layout.
+func defaultSchema(t *testing.T) fetcher.Schema {
+	t.Helper()
+	s, err := fetcher.NewSchema(cells(defaultHeader), fetcher.Layout{})
+	require.NoError(t, err)
+	return s
+}
 
+func TestParseRow_ValidData(t *testing.T) {
 	row := []any{
 		"22/12/2024",    // date
 		"13,224,723.00", // GMV
@@ -19,14 +32,12 @@ func TestParseRow_ValidData(t *testing.T) {
 		"5",             // BE pods
 	}
 
-	daily, err := i.parseRow(row, 1)
+	daily, _, err := defaultSchema(t).ParseRow(cells(row), 1)
 	assert.NoError(t, err)
 	assert.Equal(t, 13224723.00, daily.GMV)
 }
 
 func TestParseRow_DateParsed(t *testing.T) {
-	i := &impl{}
-
 	row := []any{
 		"22/12/2024",
 		"1000",
@@ -34,16 +45,14 @@ func TestParseRow_DateParsed(t *testing.T) {
 		"50",
 	}
 
-	daily, err := i.parseRow(row, 2)
+	daily, _, err := defaultSchema(t).ParseRow(cells(row), 2)
 	assert.NoError(t, err)
 
-	expectedDate, _ := time.Parse(dateLayout, "22/12/2024")
+	expectedDate, _ := time.Parse(fetcher.DateLayout, "22/12/2024")
 	assert.Equal(t, expectedDate, daily.Date)
 }
 
 func TestParseRow_OptionalFieldsMissing(t *testing.T) {
-	i := &impl{}
-
 	row := []any{
 		"01/01/2025",
 		"1000",
@@ -53,15 +62,13 @@ func TestParseRow_OptionalFieldsMissing(t *testing.T) {
 		"", // BE pods missing
 	}
 
-	daily, err := i.parseRow(row, 3)
+	daily, _, err := defaultSchema(t).ParseRow(cells(row), 3)
 	assert.NoError(t, err)
 	assert.Nil(t, daily.FEPods)
 	assert.Nil(t, daily.BEPods)
 }
 
 func TestParseRow_InvalidDate(t *testing.T) {
-	i := &impl{}
-
 	row := []any{
 		"invalid-date",
 		"1000",
@@ -69,13 +76,11 @@ func TestParseRow_InvalidDate(t *testing.T) {
 		"50",
 	}
 
-	_, err := i.parseRow(row, 4)
+	_, _, err := defaultSchema(t).ParseRow(cells(row), 4)
 	assert.Error(t, err)
 }
 
 func TestParseRow_InvalidGMV(t *testing.T) {
-	i := &impl{}
-
 	row := []any{
 		"01/01/2025",
 		"13,abc",
@@ -83,13 +88,11 @@ func TestParseRow_InvalidGMV(t *testing.T) {
 		"50",
 	}
 
-	_, err := i.parseRow(row, 5)
+	_, _, err := defaultSchema(t).ParseRow(cells(row), 5)
 	assert.Error(t, err)
 }
 
 func TestParseRow_InvalidUsers(t *testing.T) {
-	i := &impl{}
-
 	row := []any{
 		"01/01/2025",
 		"1000",
@@ -97,13 +100,11 @@ func TestParseRow_InvalidUsers(t *testing.T) {
 		"50",
 	}
 
-	_, err := i.parseRow(row, 6)
+	_, _, err := defaultSchema(t).ParseRow(cells(row), 6)
 	assert.Error(t, err)
 }
 
 func TestParseRow_InvalidMarketingCost(t *testing.T) {
-	i := &impl{}
-
 	row := []any{
 		"01/01/2025",
 		"1000",
@@ -111,6 +112,115 @@ func TestParseRow_InvalidMarketingCost(t *testing.T) {
 		"abc",
 	}
 
-	_, err := i.parseRow(row, 7)
+	_, _, err := defaultSchema(t).ParseRow(cells(row), 7)
+	assert.Error(t, err)
+}
+
+func TestParseValues_ReorderedColumnsWithExtras(t *testing.T) {
+	i := &impl{opts: Options{Layout: fetcher.Layout{
+		DateLayout: "2006-01-02",
+		Columns:    fetcher.Columns{fetcher.FieldGMV: "Revenue", fetcher.FieldDate: "Day"},
+	}}}
+
+	values := [][]any{
+		{"Notes", "BEPods", "Revenue", "Day", "MarketingCost", "Region", "Users", "FEPods"},
+		{"promo", "5", "1,000.50", "2025-01-02", "50", "EU", "12", "10"},
+	}
+
+	rows, _, err := i.parseValues(values, 1)
+	require.NoError(t, err)
+	require.Len(t, rows, 1)
+
+	assert.Equal(t, time.Date(2025, 1, 2, 0, 0, 0, 0, time.UTC), rows[0].Date)
+	assert.Equal(t, 1000.50, rows[0].GMV)
+	assert.Equal(t, 12.0, rows[0].Users)
+	assert.Equal(t, 50.0, rows[0].MarketingCost)
+	require.True(t, rows[0].HasPods())
+	assert.Equal(t, 10, *rows[0].FEPods)
+	assert.Equal(t, 5, *rows[0].BEPods)
+}
+
+func TestParseValues_MissingRequiredHeader(t *testing.T) {
+	i := &impl{}
+
+	_, _, err := i.parseValues([][]any{{"Date", "GMV", "MarketingCost"}}, 1)
 	assert.Error(t, err)
 }
+
+func TestParseValues_Empty(t *testing.T) {
+	i := &impl{}
+
+	_, _, err := i.parseValues(nil, 1)
+	assert.Error(t, err)
+}
+
+func TestOptions_ReadRange(t *testing.T) {
+	assert.Equal(t, "'Sheet1'!A:Z", Options{}.readRange())
+	assert.Equal(t, "'Finance KPIs'!B2:K", Options{SheetName: "Finance KPIs", Range: "B2:K"}.readRange())
+	assert.Equal(t, "'Bob''s tab'!A:Z", Options{SheetName: "Bob's tab"}.readRange())
+}
+
+func TestParseValues_Report(t *testing.T) {
+	i := &impl{}
+
+	values := [][]any{
+		defaultHeader,
+		{"01/01/2025", "1000", "1", "50", "1", "1"},
+		{"bad-date", "1000", "1", "50", "1", "1"},
+		{"01/01/2025", "2000", "2", "60", "2", "2"},
+		{"04/01/2025", "1000", "1", "50", "x", "1"},
+	}
+
+	rows, rep, err := i.parseValues(values, 1)
+	require.NoError(t, err)
+	require.Len(t, rows, 2)
+
+	assert.Equal(t, 4, rep.RowsRead)
+	assert.Equal(t, 2, rep.RowsAccepted)
+	require.Len(t, rep.Rejected, 2)
+	assert.Equal(t, 3, rep.Rejected[0].Row)
+	assert.Equal(t, 4, rep.Rejected[1].Row)
+	require.Len(t, rep.Warnings, 1)
+	assert.Equal(t, 5, rep.Warnings[0].Row)
+	assert.Nil(t, rows[1].FEPods)
+	assert.Len(t, rep.DuplicateDates, 1)
+	require.Len(t, rep.Gaps, 1)
+	assert.Equal(t, 2, rep.Gaps[0].MissingDays)
+	assert.InDelta(t, 0.5, rep.RejectionRate(), 1e-9)
+}
+
+func TestParseValues_RowsNumberedFromRangeStart(t *testing.T) {
+	i := &impl{}
+
+	values := [][]any{
+		defaultHeader,
+		{"01/01/2025", "1000", "1", "50", "1", "1"},
+		{"bad-date", "1000", "1", "50", "1", "1"},
+	}
+
+	_, rep, err := i.parseValues(values, 5)
+	require.NoError(t, err)
+	require.Len(t, rep.Rejected, 1)
+	assert.Equal(t, 7, rep.Rejected[0].Row)
+}
+
+func TestStartRow(t *testing.T) {
+	assert.Equal(t, 5, startRow("'Sheet1'!B5:K"))
+	assert.Equal(t, 12, startRow("'Bob''s tab'!$C$12:$F$40"))
+	assert.Equal(t, 3, startRow("3:9"))
+	assert.Equal(t, 1, startRow("'Sheet1'!A:Z"))
+	assert.Equal(t, 1, startRow("A:Z"))
+	assert.Equal(t, 1, startRow(""))
+}
+
+func TestIsTransient(t *testing.T) {
+	wrap := func(code int) error {
+		return fmt.Errorf("failed to fetch sheet data: %w", &googleapi.Error{Code: code})
+	}
+
+	assert.True(t, isTransient(wrap(429)))
+	assert.True(t, isTransient(wrap(503)))
+	assert.False(t, isTransient(wrap(403)))
+	assert.False(t, isTransient(wrap(404)))
+	assert.False(t, isTransient(errors.New("boom")))
+}
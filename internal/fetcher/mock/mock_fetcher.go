@@ -1,7 +1,10 @@
 package mock
 
 import (
+	"context"
+
 	"github.com/stretchr/testify/mock"
+	"github.com/thisiscetin/podpredict/internal/fetcher"
 	"github.com/thisiscetin/podpredict/internal/metrics"
 )
 
@@ -11,7 +14,7 @@ type MockFetcher struct {
 }
 
 // Fetch mocks the Fetch method to return predefined data.
-func (m *MockFetcher) Fetch() ([]metrics.Daily, error) {
-	args := m.Called()
-	return args.Get(0).([]metrics.Daily), args.Error(1)
+func (m *MockFetcher) Fetch(ctx context.Context) ([]metrics.Daily, fetcher.Report, error) {
+	args := m.Called(ctx)
+	return args.Get(0).([]metrics.Daily), args.Get(1).(fetcher.Report), args.Error(2)
 }
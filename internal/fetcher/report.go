@@ -0,0 +1,137 @@
+package fetcher
+
+import (
+	"errors"
+	"fmt"
+	"sort"
+	"time"
+
+	"github.com/thisiscetin/podpredict/internal/metrics"
+)
+
+// ErrRejectThreshold is returned by Report.CheckRejectionRate when too many
+// source rows were rejected during ingestion.
+var ErrRejectThreshold = errors.New("rejected rows exceed threshold")
+
+// RowIssue describes a problem with a single source row.
+// Row is the 1-based row number in the source, header included.
+type RowIssue struct {
+	Row    int    `json:"row"`
+	Reason string `json:"reason"`
+}
+
+// DateGap describes a run of missing days between two consecutive accepted rows.
+type DateGap struct {
+	After       time.Time `json:"after"`
+	Before      time.Time `json:"before"`
+	MissingDays int       `json:"missing_days"`
+}
+
+// Report summarizes the data quality of a single Fetch.
+type Report struct {
+	// Source identifies where the rows were read from.
+	Source string `json:"source"`
+	// FetchedAt is when the fetch completed, in UTC.
+	FetchedAt time.Time `json:"fetched_at"`
+	// RowsRead counts data rows seen, header excluded.
+	RowsRead int `json:"rows_read"`
+	// RowsAccepted counts rows returned to the caller.
+	RowsAccepted int `json:"rows_accepted"`
+	// Rejected lists rows that were dropped and why.
+	Rejected []RowIssue `json:"rejected"`
+	// Warnings lists rows that were kept but lost optional values,
+	// such as pod counts that failed to parse.
+	Warnings []RowIssue `json:"warnings"`
+	// DuplicateDates lists dates that appeared on more than one row.
+	// Only the first row for each date is accepted.
+	DuplicateDates []time.Time `json:"duplicate_dates"`
+	// Gaps lists missing days between accepted rows.
+	Gaps []DateGap `json:"gaps"`
+}
+
+// RejectionRate returns the fraction of read rows that were rejected.
+func (r Report) RejectionRate() float64 {
+	if r.RowsRead == 0 {
+		return 0
+	}
+	return float64(len(r.Rejected)) / float64(r.RowsRead)
+}
+
+// CheckRejectionRate returns ErrRejectThreshold if RejectionRate exceeds max.
+func (r Report) CheckRejectionRate(max float64) error {
+	if rate := r.RejectionRate(); rate > max {
+		return fmt.Errorf("%w: %d of %d rows (%.1f%%) rejected, max %.1f%%",
+			ErrRejectThreshold, len(r.Rejected), r.RowsRead, rate*100, max*100)
+	}
+	return nil
+}
+
+// Ingest parses rows with a Schema and records every decision in a Report.
+// Fetcher implementations feed it one row at a time and call Result once.
+type Ingest struct {
+	schema Schema
+	report Report
+	seen   map[time.Time]int
+	out    []metrics.Daily
+}
+
+// NewIngest returns an Ingest that parses rows read from source with schema.
+func NewIngest(source string, schema Schema) *Ingest {
+	return &Ingest{
+		schema: schema,
+		report: Report{
+			Source:   source,
+			Rejected: []RowIssue{},
+			Warnings: []RowIssue{},
+		},
+		seen: make(map[time.Time]int),
+	}
+}
+
+// Add parses a single data row. Invalid rows and rows repeating an
+// already accepted date are rejected; the first occurrence of a date wins.
+func (in *Ingest) Add(row []string, rowNum int) {
+	in.report.RowsRead++
+
+	d, warnings, err := in.schema.ParseRow(row, rowNum)
+	if err != nil {
+		in.reject(rowNum, err.Error())
+		return
+	}
+	if first, dup := in.seen[d.Date]; dup {
+		if first > 0 {
+			in.report.DuplicateDates = append(in.report.DuplicateDates, d.Date)
+			in.seen[d.Date] = -first // report each date once
+		}
+		in.reject(rowNum, fmt.Sprintf("duplicate date %s", d.Date.Format(time.DateOnly)))
+		return
+	}
+	in.seen[d.Date] = rowNum
+
+	for _, w := range warnings {
+		in.report.Warnings = append(in.report.Warnings, RowIssue{Row: rowNum, Reason: w})
+	}
+	in.out = append(in.out, d)
+}
+
+// Result returns the accepted rows sorted by date together with the Report.
+func (in *Ingest) Result() ([]metrics.Daily, Report) {
+	sort.SliceStable(in.out, func(a, b int) bool { return in.out[a].Date.Before(in.out[b].Date) })
+
+	rep := in.report
+	rep.FetchedAt = time.Now().UTC()
+	rep.RowsAccepted = len(in.out)
+	rep.DuplicateDates = append([]time.Time{}, rep.DuplicateDates...)
+	rep.Gaps = []DateGap{}
+	for i := 1; i < len(in.out); i++ {
+		prev, cur := in.out[i-1].Date, in.out[i].Date
+		if days := int(cur.Sub(prev).Hours() / 24); days > 1 {
+			rep.Gaps = append(rep.Gaps, DateGap{After: prev, Before: cur, MissingDays: days - 1})
+		}
+	}
+	return in.out, rep
+}
+
+func (in *Ingest) reject(rowNum int, reason string) {
+	in.report.Rejected = append(in.report.Rejected, RowIssue{Row: rowNum, Reason: reason})
+}
@@ -0,0 +1,37 @@
+package fetcher
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestReport_CheckRejectionRate(t *testing.T) {
+	r := Report{RowsRead: 10, Rejected: make([]RowIssue, 3)}
+
+	assert.InDelta(t, 0.3, r.RejectionRate(), 1e-9)
+	assert.NoError(t, r.CheckRejectionRate(0.3))
+	assert.ErrorIs(t, r.CheckRejectionRate(0.2), ErrRejectThreshold)
+}
+
+func TestReport_RejectionRate_NoRows(t *testing.T) {
+	assert.Equal(t, 0.0, Report{}.RejectionRate())
+}
+
+func TestIngest_WarnsOnBadPods(t *testing.T) {
+	s, err := NewSchema([]string{"Date", "GMV", "Users", "MarketingCost", "FEPods", "BEPods"}, Layout{})
+	require.NoError(t, err)
+
+	in := NewIngest("test", s)
+	in.Add([]string{"02/01/2025", "10", "2", "3", "abc", "4"}, 2)
+	in.Add([]string{"01/01/2025", "10", "2", "3", "1", "1"}, 3)
+	rows, rep := in.Result()
+
+	require.Len(t, rows, 2)
+	assert.True(t, rows[0].Date.Before(rows[1].Date), "rows must be sorted by date")
+	assert.Empty(t, rep.Rejected)
+	require.Len(t, rep.Warnings, 1)
+	assert.Equal(t, 2, rep.Warnings[0].Row)
+	assert.Empty(t, rep.Gaps)
+}
@@ -0,0 +1,204 @@
+package resilient
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"sync"
+	"time"
+
+	"github.com/thisiscetin/podpredict/internal/fetcher"
+	"github.com/thisiscetin/podpredict/internal/metrics"
+)
+
+// ErrCircuitOpen is returned without calling the wrapped Fetcher while the
+// circuit breaker is open.
+var ErrCircuitOpen = errors.New("fetcher circuit breaker open")
+
+// Options configures the resilience policies applied around a Fetcher.
+// Zero values fall back to the defaults documented on each field.
+type Options struct {
+	// Timeout bounds each individual attempt. 0 disables the per-attempt timeout.
+	Timeout time.Duration
+	// MaxAttempts is the total number of attempts per Fetch, default 3.
+	MaxAttempts int
+	// BaseDelay is the backoff before the second attempt, default 200ms.
+	// It doubles for every further attempt.
+	BaseDelay time.Duration
+	// MaxDelay caps the backoff between attempts, default 5s.
+	MaxDelay time.Duration
+	// BreakerThreshold is the number of consecutive failed Fetch calls that
+	// opens the circuit. 0 disables the circuit breaker.
+	BreakerThreshold int
+	// BreakerCooldown is how long the circuit stays open before a single
+	// trial call is let through, default 30s.
+	BreakerCooldown time.Duration
+	// IsTransient decides which errors are retried, default fetcher.IsTransient.
+	IsTransient func(error) bool
+}
+
+func (o Options) withDefaults() Options {
+	if o.MaxAttempts <= 0 {
+		o.MaxAttempts = 3
+	}
+	if o.BaseDelay <= 0 {
+		o.BaseDelay = 200 * time.Millisecond
+	}
+	if o.MaxDelay <= 0 {
+		o.MaxDelay = 5 * time.Second
+	}
+	if o.BreakerCooldown <= 0 {
+		o.BreakerCooldown = 30 * time.Second
+	}
+	if o.IsTransient == nil {
+		o.IsTransient = fetcher.IsTransient
+	}
+	return o
+}
+
+// impl decorates a fetcher.Fetcher with timeouts, retries and a circuit breaker.
+// It is safe for concurrent use.
+type impl struct {
+	next fetcher.Fetcher
+	opts Options
+
+	// now and sleep are replaced in tests.
+	now   func() time.Time
+	sleep func(ctx context.Context, d time.Duration) error
+
+	mu        sync.Mutex
+	failures  int       // consecutive failed Fetch calls
+	openUntil time.Time // circuit is open while now is before openUntil
+	probing   bool      // a half-open trial call is in flight
+}
+
+// NewFetcher wraps next so that every Fetch applies opts.
+func NewFetcher(next fetcher.Fetcher, opts Options) fetcher.Fetcher {
+	return &impl{
+		next:  next,
+		opts:  opts.withDefaults(),
+		now:   time.Now,
+		sleep: sleepCtx,
+	}
+}
+
+// Fetch calls the wrapped Fetcher, retrying transient errors with
+// exponential backoff until MaxAttempts is reached or ctx is done.
+func (i *impl) Fetch(ctx context.Context) ([]metrics.Daily, fetcher.Report, error) {
+	if err := i.allow(); err != nil {
+		return nil, fetcher.Report{}, err
+	}
+
+	var lastErr error
+	for attempt := 1; attempt <= i.opts.MaxAttempts; attempt++ {
+		if attempt > 1 {
+			if err := i.sleep(ctx, i.backoff(attempt)); err != nil {
+				break
+			}
+		}
+
+		out, rep, err := i.attempt(ctx)
+		if err == nil {
+			i.record(nil)
+			return out, rep, nil
+		}
+		lastErr = err
+		if ctx.Err() != nil || !i.opts.IsTransient(err) {
+			break
+		}
+	}
+
+	// A caller giving up says nothing about the health of the source,
+	// so it does not count towards opening the circuit.
+	if ctxErr := ctx.Err(); ctxErr != nil {
+		i.release()
+		if !errors.Is(lastErr, ctxErr) {
+			lastErr = errors.Join(lastErr, ctxErr)
+		}
+		return nil, fetcher.Report{}, lastErr
+	}
+	i.record(lastErr)
+	return nil, fetcher.Report{}, lastErr
+}
+
+// attempt runs a single call to the wrapped Fetcher under the per-attempt timeout.
+func (i *impl) attempt(ctx context.Context) ([]metrics.Daily, fetcher.Report, error) {
+	if i.opts.Timeout > 0 {
+		var cancel context.CancelFunc
+		ctx, cancel = context.WithTimeout(ctx, i.opts.Timeout)
+		defer cancel()
+	}
+	return i.next.Fetch(ctx)
+}
+
+// backoff returns the delay before the given attempt (2-based).
+func (i *impl) backoff(attempt int) time.Duration {
+	d := i.opts.BaseDelay
+	for n := 2; n < attempt; n++ {
+		d *= 2
+		if d >= i.opts.MaxDelay {
+			return i.opts.MaxDelay
+		}
+	}
+	return min(d, i.opts.MaxDelay)
+}
+
+// allow reports whether a call may proceed under the circuit breaker.
+// Once the cooldown has elapsed a single trial call is let through.
+func (i *impl) allow() error {
+	if i.opts.BreakerThreshold <= 0 {
+		return nil
+	}
+	i.mu.Lock()
+	defer i.mu.Unlock()
+
+	if i.failures < i.opts.BreakerThreshold {
+		return nil
+	}
+	if i.now().Before(i.openUntil) || i.probing {
+		return fmt.Errorf("%w after %d consecutive failures", ErrCircuitOpen, i.failures)
+	}
+	i.probing = true
+	return nil
+}
+
+// record updates the circuit breaker with the outcome of a Fetch.
+func (i *impl) record(err error) {
+	if i.opts.BreakerThreshold <= 0 {
+		return
+	}
+	i.mu.Lock()
+	defer i.mu.Unlock()
+
+	i.probing = false
+	if err == nil {
+		i.failures = 0
+		return
+	}
+	i.failures++
+	if i.failures >= i.opts.BreakerThreshold {
+		i.openUntil = i.now().Add(i.opts.BreakerCooldown)
+	}
+}
+
+// release ends a half-open trial call without recording an outcome.
+func (i *impl) release() {
+	if i.opts.BreakerThreshold <= 0 {
+		return
+	}
+	i.mu.Lock()
+	i.probing = false
+	i.mu.Unlock()
+}
+
+// sleepCtx waits for d or until ctx is done.
+func sleepCtx(ctx context.Context, d time.Duration) error {
+	t := time.NewTimer(d)
+	defer t.Stop()
+	select {
+	case <-ctx.Done():
+		return ctx.Err()
+	case <-t.C:
+		return nil
+	}
+}
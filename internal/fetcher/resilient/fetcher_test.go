@@ -0,0 +1,133 @@
+package resilient
+
+import (
+	"context"
+	"errors"
+	"sync"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"github.com/thisiscetin/podpredict/internal/fetcher"
+	"github.com/thisiscetin/podpredict/internal/metrics"
+)
+
+// scriptedFetcher returns errs in order, then succeeds.
+type scriptedFetcher struct {
+	mu    sync.Mutex
+	errs  []error
+	calls int
+	block bool // wait for ctx to be done instead of returning
+}
+
+func (f *scriptedFetcher) Fetch(ctx context.Context) ([]metrics.Daily, fetcher.Report, error) {
+	f.mu.Lock()
+	f.calls++
+	n := f.calls
+	f.mu.Unlock()
+
+	if f.block {
+		<-ctx.Done()
+		return nil, fetcher.Report{}, ctx.Err()
+	}
+	if n <= len(f.errs) {
+		return nil, fetcher.Report{}, f.errs[n-1]
+	}
+	return []metrics.Daily{{}}, fetcher.Report{RowsRead: 1}, nil
+}
+
+// newTestFetcher wires impl with a fake clock and a sleep that records delays.
+func newTestFetcher(next fetcher.Fetcher, opts Options) (*impl, *[]time.Duration, *time.Time) {
+	clock := time.Unix(0, 0)
+	var delays []time.Duration
+	i := NewFetcher(next, opts).(*impl)
+	i.now = func() time.Time { return clock }
+	i.sleep = func(ctx context.Context, d time.Duration) error {
+		delays = append(delays, d)
+		return ctx.Err()
+	}
+	return i, &delays, &clock
+}
+
+var errTransient = fetcher.Transient(errors.New("503"))
+
+func TestFetch_RetriesTransientWithExponentialBackoff(t *testing.T) {
+	next := &scriptedFetcher{errs: []error{errTransient, errTransient, errTransient}}
+	f, delays, _ := newTestFetcher(next, Options{MaxAttempts: 4, BaseDelay: 100 * time.Millisecond, MaxDelay: 300 * time.Millisecond})
+
+	out, rep, err := f.Fetch(context.Background())
+	require.NoError(t, err)
+	assert.Len(t, out, 1)
+	assert.Equal(t, 1, rep.RowsRead)
+	assert.Equal(t, 4, next.calls)
+	assert.Equal(t, []time.Duration{100 * time.Millisecond, 200 * time.Millisecond, 300 * time.Millisecond}, *delays)
+}
+
+func TestFetch_DoesNotRetryPermanentErrors(t *testing.T) {
+	perm := errors.New("invalid sheet header")
+	next := &scriptedFetcher{errs: []error{perm}}
+	f, _, _ := newTestFetcher(next, Options{MaxAttempts: 5})
+
+	_, _, err := f.Fetch(context.Background())
+	assert.ErrorIs(t, err, perm)
+	assert.Equal(t, 1, next.calls)
+}
+
+func TestFetch_GivesUpAfterMaxAttempts(t *testing.T) {
+	next := &scriptedFetcher{errs: []error{errTransient, errTransient, errTransient}}
+	f, _, _ := newTestFetcher(next, Options{MaxAttempts: 2})
+
+	_, _, err := f.Fetch(context.Background())
+	assert.ErrorIs(t, err, errTransient)
+	assert.Equal(t, 2, next.calls)
+}
+
+func TestFetch_PerAttemptTimeout(t *testing.T) {
+	next := &scriptedFetcher{block: true}
+	f := NewFetcher(next, Options{Timeout: 10 * time.Millisecond, MaxAttempts: 2, BaseDelay: time.Millisecond})
+
+	_, _, err := f.Fetch(context.Background())
+	assert.ErrorIs(t, err, context.DeadlineExceeded)
+	assert.Equal(t, 2, next.calls, "timeouts are transient and retried")
+}
+
+func TestFetch_CircuitBreakerOpensAndRecovers(t *testing.T) {
+	perm := errors.New("down")
+	next := &scriptedFetcher{errs: []error{perm, perm}}
+	f, _, clock := newTestFetcher(next, Options{MaxAttempts: 1, BreakerThreshold: 2, BreakerCooldown: time.Minute})
+	ctx := context.Background()
+
+	_, _, err := f.Fetch(ctx)
+	require.ErrorIs(t, err, perm)
+	_, _, err = f.Fetch(ctx)
+	require.ErrorIs(t, err, perm)
+
+	// Open: the wrapped fetcher is not called.
+	_, _, err = f.Fetch(ctx)
+	require.ErrorIs(t, err, ErrCircuitOpen)
+	assert.Equal(t, 2, next.calls)
+
+	// After the cooldown a trial call goes through and closes the circuit.
+	*clock = clock.Add(time.Minute)
+	_, _, err = f.Fetch(ctx)
+	require.NoError(t, err)
+	_, _, err = f.Fetch(ctx)
+	require.NoError(t, err)
+	assert.Equal(t, 4, next.calls)
+}
+
+func TestFetch_CancelledContextDoesNotTripBreaker(t *testing.T) {
+	next := &scriptedFetcher{errs: []error{errTransient, errTransient}}
+	f, _, _ := newTestFetcher(next, Options{MaxAttempts: 3, BreakerThreshold: 1})
+
+	ctx, cancel := context.WithCancel(context.Background())
+	cancel()
+
+	_, _, err := f.Fetch(ctx)
+	require.ErrorIs(t, err, context.Canceled)
+
+	_, _, err = f.Fetch(context.Background())
+	assert.NotErrorIs(t, err, ErrCircuitOpen)
+}
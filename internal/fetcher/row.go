@@ -0,0 +1,166 @@
+package fetcher
+
+import (
+	"fmt"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/thisiscetin/podpredict/internal/metrics"
+)
+
+// Schema locates the mapped columns of a table by header name and
+// parses data rows into metrics.Daily. Build one with NewSchema.
+type Schema struct {
+	index      map[Field]int
+	dateLayout string
+}
+
+// NewSchema resolves the columns described by layout against header.
+// Header names are matched case-insensitively, ignoring surrounding spaces,
+// so columns may appear in any order and unrelated columns are ignored.
+// It returns an error if a required column is missing or a header is ambiguous.
+func NewSchema(header []string, layout Layout) (Schema, error) {
+	layout = layout.withDefaults()
+
+	positions := make(map[string]int, len(header))
+	for idx, h := range header {
+		key := normalizeHeader(h)
+		if key == "" {
+			continue
+		}
+		if _, dup := positions[key]; dup {
+			positions[key] = -1 // ambiguous; only an error if it is mapped
+			continue
+		}
+		positions[key] = idx
+	}
+
+	s := Schema{index: make(map[Field]int, len(fields)), dateLayout: layout.DateLayout}
+	for _, f := range fields {
+		name := layout.Columns[f]
+		idx, ok := positions[normalizeHeader(name)]
+		switch {
+		case ok && idx < 0:
+			return Schema{}, fmt.Errorf("column %q for %s appears more than once in header", name, f)
+		case ok:
+			s.index[f] = idx
+		case f.required():
+			return Schema{}, fmt.Errorf("column %q for %s not found in header", name, f)
+		}
+	}
+	return s, nil
+}
+
+// ParseRow parses a single data row into metrics.Daily. Date, GMV, Users and
+// MarketingCost are required; FEPods and BEPods are optional and left nil
+// when empty or unmapped. Pod values that fail to parse do not reject the
+// row; they are left nil and described in the returned warnings instead.
+// rowNum is only used to annotate errors.
+func (s Schema) ParseRow(row []string, rowNum int) (metrics.Daily, []string, error) {
+	// Date
+	dateStr, err := s.required(row, FieldDate, rowNum)
+	if err != nil {
+		return metrics.Daily{}, nil, err
+	}
+	date, err := time.Parse(s.dateLayout, dateStr)
+	if err != nil {
+		return metrics.Daily{}, nil, fmt.Errorf("row %d: failed to parse date: %v", rowNum, err)
+	}
+
+	// GMV
+	gmvStr, err := s.required(row, FieldGMV, rowNum)
+	if err != nil {
+		return metrics.Daily{}, nil, err
+	}
+	gmv, err := ParseFloat(gmvStr)
+	if err != nil {
+		return metrics.Daily{}, nil, fmt.Errorf("row %d: failed to parse GMV: %v", rowNum, err)
+	}
+
+	// Users
+	usersStr, err := s.required(row, FieldUsers, rowNum)
+	if err != nil {
+		return metrics.Daily{}, nil, err
+	}
+	users, err := ParseInt(usersStr)
+	if err != nil {
+		return metrics.Daily{}, nil, fmt.Errorf("row %d: failed to parse Users: %v", rowNum, err)
+	}
+
+	// Marketing Cost
+	marketingStr, err := s.required(row, FieldMarketingCost, rowNum)
+	if err != nil {
+		return metrics.Daily{}, nil, err
+	}
+	marketingCost, err := ParseFloat(marketingStr)
+	if err != nil {
+		return metrics.Daily{}, nil, fmt.Errorf("row %d: failed to parse Marketing Cost: %v", rowNum, err)
+	}
+
+	var warnings []string
+
+	// FE Pods (optional)
+	var fePods *int
+	if v := s.cell(row, FieldFEPods); v != "" {
+		n, err := ParseInt(v)
+		if err == nil {
+			fePods = &n
+		} else {
+			warnings = append(warnings, fmt.Sprintf("failed to parse FE Pods: %v", err))
+		}
+	}
+
+	// BE Pods (optional)
+	var bePods *int
+	if v := s.cell(row, FieldBEPods); v != "" {
+		n, err := ParseInt(v)
+		if err == nil {
+			bePods = &n
+		} else {
+			warnings = append(warnings, fmt.Sprintf("failed to parse BE Pods: %v", err))
+		}
+	}
+
+	dailyMetric, err := metrics.NewDaily(date, gmv, users, marketingCost, fePods, bePods)
+	if err != nil {
+		return metrics.Daily{}, nil, fmt.Errorf("row %d: failed to create Daily metric: %v", rowNum, err)
+	}
+
+	return dailyMetric, warnings, nil
+}
+
+// cell returns the trimmed value of field f in row, or "" if the column is
+// unmapped or the row is too short.
+func (s Schema) cell(row []string, f Field) string {
+	idx, ok := s.index[f]
+	if !ok || idx >= len(row) {
+		return ""
+	}
+	return strings.TrimSpace(row[idx])
+}
+
+// required returns the value of field f in row, or an error if it is empty.
+func (s Schema) required(row []string, f Field, rowNum int) (string, error) {
+	v := s.cell(row, f)
+	if v == "" {
+		return "", fmt.Errorf("row %d: missing %s", rowNum, f)
+	}
+	return v, nil
+}
+
+// normalizeHeader folds a header name for case- and space-insensitive matching.
+func normalizeHeader(h string) string {
+	return strings.ToLower(strings.TrimSpace(h))
+}
+
+// ParseFloat cleans a string of commas and surrounding spaces and parses it as float64.
+func ParseFloat(s string) (float64, error) {
+	clean := strings.ReplaceAll(strings.TrimSpace(s), ",", "")
+	return strconv.ParseFloat(clean, 64)
+}
+
+// ParseInt parses a string as int, ignoring surrounding spaces.
+func ParseInt(s string) (int, error) {
+	return strconv.Atoi(strings.TrimSpace(s))
+}
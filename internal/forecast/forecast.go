@@ -0,0 +1,180 @@
+// Package forecast plans pods for a range of future days. A
+// model.Forecaster forecasts every day from the date alone. Other models
+// score each day on its KPIs from the training data when present, or on
+// KPIs extrapolated from their recent trend and weekly pattern.
+package forecast
+
+import (
+	"errors"
+	"sort"
+	"time"
+
+	"github.com/thisiscetin/podpredict/internal/metrics"
+	"github.com/thisiscetin/podpredict/internal/model"
+)
+
+// Where the KPIs of a Day come from.
+const (
+	// KPIsSheet marks KPIs read from the training data row of the day.
+	KPIsSheet = "sheet"
+	// KPIsExtrapolated marks KPIs extrapolated from earlier days.
+	KPIsExtrapolated = "extrapolated"
+	// KPIsNone marks days forecast by a model.Forecaster without KPIs.
+	KPIsNone = "none"
+)
+
+// DefaultWindow is the number of most recent days KPIs are extrapolated from.
+const DefaultWindow = 28
+
+// ErrNoHistory is returned by Run when KPIs must be extrapolated but the
+// history has no rows.
+var ErrNoHistory = errors.New("no KPI history to extrapolate from")
+
+// Day is the pods forecast for one UTC calendar day.
+type Day struct {
+	Date time.Time
+	// Input holds the KPIs the pods were predicted from; zero for KPIsNone.
+	Input  model.Features
+	KPIs   string
+	FEPods model.FEPods
+	BEPods model.BEPods
+}
+
+// Run forecasts every day from from to to, both inclusive, with m.
+// history is the data m was trained on, including rows without pods.
+// A model.Forecaster ignores features, so it forecasts every day by date,
+// even days with KPIs in the history.
+func Run(m model.Model, history []metrics.Daily, from, to time.Time) ([]Day, error) {
+	sheet := make(map[time.Time]model.Features, len(history))
+	for _, d := range history {
+		sheet[day(d.Date)] = model.FeaturesFromDaily(d)
+	}
+	fc, _ := m.(model.Forecaster)
+
+	var (
+		out []Day
+		ex  *Extrapolator
+	)
+	for d := day(from); !d.After(day(to)); d = d.AddDate(0, 0, 1) {
+		fd := Day{Date: d}
+		if fc != nil {
+			fe, be, err := fc.Forecast(d)
+			if err != nil {
+				return nil, err
+			}
+			fd.KPIs, fd.FEPods, fd.BEPods = KPIsNone, fe, be
+			out = append(out, fd)
+			continue
+		}
+		if in, ok := sheet[d]; ok {
+			fd.Input, fd.KPIs = in, KPIsSheet
+		} else {
+			if ex == nil {
+				if ex = NewExtrapolator(history, DefaultWindow); ex == nil {
+					return nil, ErrNoHistory
+				}
+			}
+			fd.Input, fd.KPIs = ex.KPIs(d), KPIsExtrapolated
+		}
+
+		fe, be, err := m.Predict(&fd.Input)
+		if err != nil {
+			return nil, err
+		}
+		fd.FEPods, fd.BEPods = fe, be
+		out = append(out, fd)
+	}
+	return out, nil
+}
+
+// day truncates t to its UTC date.
+func day(t time.Time) time.Time {
+	return t.UTC().Truncate(24 * time.Hour)
+}
+
+// Extrapolator projects each KPI with a linear trend plus a mean offset
+// per weekday, both fitted on the most recent days of the history.
+type Extrapolator struct {
+	origin time.Time
+	kpis   [3]trend // GMV, Users, MarketingCost
+}
+
+// trend is value = intercept + slope × days since origin + weekday offset.
+type trend struct {
+	intercept, slope float64
+	weekday          [7]float64
+}
+
+// NewExtrapolator fits an Extrapolator on the rows of history within
+// window days of its last row. It returns nil for an empty history.
+func NewExtrapolator(history []metrics.Daily, window int) *Extrapolator {
+	if len(history) == 0 {
+		return nil
+	}
+	rows := append([]metrics.Daily(nil), history...)
+	sort.SliceStable(rows, func(i, j int) bool { return rows[i].Date.Before(rows[j].Date) })
+	last := day(rows[len(rows)-1].Date)
+	first := last.AddDate(0, 0, -window+1)
+	for len(rows) > 1 && day(rows[0].Date).Before(first) {
+		rows = rows[1:]
+	}
+
+	e := &Extrapolator{origin: last}
+	xs := make([]float64, len(rows))
+	for i, r := range rows {
+		xs[i] = day(r.Date).Sub(last).Hours() / 24
+	}
+	for k := range e.kpis {
+		ys := make([]float64, len(rows))
+		for i, r := range rows {
+			ys[i] = r.Features()[k]
+		}
+		e.kpis[k] = fitTrend(rows, xs, ys)
+	}
+	return e
+}
+
+// fitTrend fits ys on xs by least squares, then averages the residuals
+// per weekday. A single distinct day gives a flat trend.
+func fitTrend(rows []metrics.Daily, xs, ys []float64) trend {
+	n := float64(len(xs))
+	var mx, my float64
+	for i := range xs {
+		mx += xs[i] / n
+		my += ys[i] / n
+	}
+	var sxy, sxx float64
+	for i := range xs {
+		sxy += (xs[i] - mx) * (ys[i] - my)
+		sxx += (xs[i] - mx) * (xs[i] - mx)
+	}
+	var t trend
+	if sxx > 0 {
+		t.slope = sxy / sxx
+	}
+	t.intercept = my - t.slope*mx
+
+	var counts [7]float64
+	for i, r := range rows {
+		wd := r.Date.UTC().Weekday()
+		t.weekday[wd] += ys[i] - (t.intercept + t.slope*xs[i])
+		counts[wd]++
+	}
+	for wd := range t.weekday {
+		if counts[wd] > 0 {
+			t.weekday[wd] /= counts[wd]
+		}
+	}
+	return t
+}
+
+// KPIs returns the extrapolated KPIs of date's day, floored at zero.
+func (e *Extrapolator) KPIs(date time.Time) model.Features {
+	d := day(date)
+	x := d.Sub(e.origin).Hours() / 24
+	var v [3]float64
+	for k, t := range e.kpis {
+		v[k] = max(0, t.intercept+t.slope*x+t.weekday[d.Weekday()])
+	}
+	return model.Features{GMV: v[0], Users: v[1], MarketingCost: v[2]}
+}
@@ -0,0 +1,123 @@
+package forecast
+
+import (
+	"errors"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"github.com/thisiscetin/podpredict/internal/metrics"
+	"github.com/thisiscetin/podpredict/internal/model"
+)
+
+// echoModel predicts the GMV as FE pods and the users as BE pods.
+type echoModel struct{ err error }
+
+func (m *echoModel) Train([]metrics.Daily) error { return nil }
+func (m *echoModel) Predict(in *model.Features) (model.FEPods, model.BEPods, error) {
+	return model.FEPods(in.GMV), model.BEPods(in.Users), m.err
+}
+
+// forecastingModel is an echoModel that also implements model.Forecaster.
+type forecastingModel struct{ echoModel }
+
+func (m *forecastingModel) Forecast(date time.Time) (model.FEPods, model.BEPods, error) {
+	return model.FEPods(date.Day()), 1, nil
+}
+
+func date(month time.Month, d int) time.Time {
+	return time.Date(2025, month, d, 0, 0, 0, 0, time.UTC)
+}
+
+// history returns n days from Jan 1 whose GMV grows by 10 a day, plus 50
+// on Saturdays, with 100 users and a flat marketing cost.
+func history(n int) []metrics.Daily {
+	out := make([]metrics.Daily, n)
+	for i := range out {
+		d := date(time.January, 1+i)
+		gmv := 100 + 10*float64(i)
+		if d.Weekday() == time.Saturday {
+			gmv += 50
+		}
+		out[i] = metrics.Daily{Date: d, GMV: gmv, Users: 100, MarketingCost: 5}
+	}
+	return out
+}
+
+func TestExtrapolator_FollowsTrendAndWeekday(t *testing.T) {
+	ex := NewExtrapolator(history(28), DefaultWindow)
+	require.NotNil(t, ex)
+
+	// Jan 29 is a Wednesday and Feb 1 a Saturday, 28 and 31 days after Jan 1.
+	wed := ex.KPIs(date(time.January, 29))
+	sat := ex.KPIs(date(time.February, 1))
+	assert.InDelta(t, 380, wed.GMV, 1)
+	assert.InDelta(t, 410+50, sat.GMV, 1)
+	assert.InDelta(t, 100, wed.Users, 1e-9)
+	assert.InDelta(t, 5, wed.MarketingCost, 1e-9)
+}
+
+func TestExtrapolator_UsesRecentWindowAndFloorsAtZero(t *testing.T) {
+	h := history(10)
+	// A collapse in the last days drives the trend below zero.
+	for i := 7; i < 10; i++ {
+		h[i].GMV, h[i].Users = 100-50*float64(i-6), 0
+	}
+	ex := NewExtrapolator(h, 3)
+	require.NotNil(t, ex)
+	got := ex.KPIs(date(time.January, 20))
+	assert.Equal(t, 0.0, got.GMV)
+	assert.Equal(t, 0.0, got.Users)
+
+	assert.Nil(t, NewExtrapolator(nil, DefaultWindow))
+}
+
+func TestRun_PrefersSheetKPIs(t *testing.T) {
+	h := history(28)
+	// A future sheet row carries KPIs without pods.
+	h = append(h, metrics.Daily{Date: date(time.January, 30), GMV: 7, Users: 3, MarketingCost: 1})
+
+	days, err := Run(&echoModel{}, h, date(time.January, 29), date(time.January, 31).Add(5*time.Hour))
+	require.NoError(t, err)
+	require.Len(t, days, 3)
+
+	assert.Equal(t, date(time.January, 29), days[0].Date)
+	assert.Equal(t, KPIsExtrapolated, days[0].KPIs)
+	assert.Equal(t, model.FEPods(days[0].Input.GMV), days[0].FEPods)
+
+	assert.Equal(t, date(time.January, 30), days[1].Date)
+	assert.Equal(t, KPIsSheet, days[1].KPIs)
+	assert.Equal(t, model.FEPods(7), days[1].FEPods)
+	assert.Equal(t, model.BEPods(3), days[1].BEPods)
+
+	assert.Equal(t, KPIsExtrapolated, days[2].KPIs)
+}
+
+func TestRun_UsesForecasterForEveryDay(t *testing.T) {
+	h := history(3)
+	days, err := Run(&forecastingModel{}, h, date(time.January, 3), date(time.January, 5))
+	require.NoError(t, err)
+	require.Len(t, days, 3)
+
+	// Jan 3 has a sheet row, but a Forecaster ignores its KPIs.
+	for i, d := range days {
+		assert.Equal(t, KPIsNone, d.KPIs)
+		assert.Equal(t, model.FEPods(3+i), d.FEPods)
+		assert.Equal(t, model.Features{}, d.Input)
+	}
+}
+
+func TestRun_Errors(t *testing.T) {
+	_, err := Run(&echoModel{}, nil, date(time.January, 1), date(time.January, 1))
+	assert.ErrorIs(t, err, ErrNoHistory)
+
+	boom := errors.New("boom")
+	_, err = Run(&echoModel{err: boom}, history(3), date(time.January, 4), date(time.January, 4))
+	assert.ErrorIs(t, err, boom)
+
+	days, err := Run(&echoModel{}, history(3), date(time.January, 2), date(time.January, 1))
+	require.NoError(t, err)
+	assert.Empty(t, days)
+}
@@ -0,0 +1,39 @@
+package metrics
+
+import (
+	"crypto/sha256"
+	"encoding/binary"
+	"encoding/hex"
+	"math"
+)
+
+// Fingerprint returns a hash identifying the exact rows of ds, in order,
+// formatted as "sha256:<hex>". Identical training data yields the same
+// fingerprint across runs and processes.
+func Fingerprint(ds []Daily) string {
+	h := sha256.New()
+	var buf [8]byte
+	put := func(v uint64) {
+		binary.BigEndian.PutUint64(buf[:], v)
+		h.Write(buf[:])
+	}
+	putPods := func(p *int) {
+		if p == nil {
+			h.Write([]byte{0})
+			return
+		}
+		h.Write([]byte{1})
+		put(uint64(int64(*p)))
+	}
+
+	put(uint64(len(ds)))
+	for _, d := range ds {
+		put(uint64(d.Date.UnixNano()))
+		put(math.Float64bits(d.GMV))
+		put(math.Float64bits(d.Users))
+		put(math.Float64bits(d.MarketingCost))
+		putPods(d.FEPods)
+		putPods(d.BEPods)
+	}
+	return "sha256:" + hex.EncodeToString(h.Sum(nil))
+}
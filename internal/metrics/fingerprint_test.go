@@ -0,0 +1,30 @@
+package metrics
+
+import (
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestFingerprint(t *testing.T) {
+	day := time.Date(2025, 1, 1, 0, 0, 0, 0, time.UTC)
+	fe, be := 3, 2
+	a, err := NewDaily(day, 100, 10, 5, &fe, &be)
+	require.NoError(t, err)
+	b, err := NewDaily(day.AddDate(0, 0, 1), 100, 10, 5, nil, nil)
+	require.NoError(t, err)
+
+	fp := Fingerprint([]Daily{a, b})
+	assert.True(t, strings.HasPrefix(fp, "sha256:"))
+	assert.Equal(t, fp, Fingerprint([]Daily{a, b}), "deterministic")
+	assert.NotEqual(t, fp, Fingerprint([]Daily{b, a}), "order matters")
+
+	fe2 := 4
+	c := a
+	c.FEPods = &fe2
+	assert.NotEqual(t, fp, Fingerprint([]Daily{c, b}), "pods are part of the fingerprint")
+	assert.NotEqual(t, Fingerprint(nil), Fingerprint([]Daily{b}))
+}
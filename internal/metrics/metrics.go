@@ -2,6 +2,7 @@ package metrics
 
 import (
 	"errors"
+	"math"
 	"time"
 )
 
@@ -14,8 +15,48 @@ var (
 	ErrUsersNegative = errors.New("users cannot be negative")
 	// ErrMarketingCostNegative is returned when MarketingCost is negative.
 	ErrMarketingCostNegative = errors.New("marketing cost cannot be negative")
+	// ErrGmvNotFinite is returned when GMV is NaN or infinite.
+	ErrGmvNotFinite = errors.New("gmv must be a finite number")
+	// ErrUsersNotFinite is returned when Users is NaN or infinite.
+	ErrUsersNotFinite = errors.New("users must be a finite number")
+	// ErrMarketingCostNotFinite is returned when MarketingCost is NaN or infinite.
+	ErrMarketingCostNotFinite = errors.New("marketing cost must be a finite number")
 )
 
+// FieldError reports an invalid KPI. Field is its JSON name as used by
+// model.Features: gmv, users or marketing_cost.
+type FieldError struct {
+	Field string
+	Err   error
+}
+
+func (e FieldError) Error() string { return e.Err.Error() }
+func (e FieldError) Unwrap() error { return e.Err }
+
+// ValidateKPIs checks the KPI values shared by Daily rows and prediction
+// features: each must be finite and non-negative. It returns one
+// FieldError per invalid field, in field order, or nil.
+func ValidateKPIs(gmv, users, marketingCost float64) []FieldError {
+	var errs []FieldError
+	for _, f := range []struct {
+		name                string
+		v                   float64
+		notFinite, negative error
+	}{
+		{"gmv", gmv, ErrGmvNotFinite, ErrGmvNegative},
+		{"users", users, ErrUsersNotFinite, ErrUsersNegative},
+		{"marketing_cost", marketingCost, ErrMarketingCostNotFinite, ErrMarketingCostNegative},
+	} {
+		switch {
+		case math.IsNaN(f.v) || math.IsInf(f.v, 0):
+			errs = append(errs, FieldError{Field: f.name, Err: f.notFinite})
+		case f.v < 0:
+			errs = append(errs, FieldError{Field: f.name, Err: f.negative})
+		}
+	}
+	return errs
+}
+
 // Daily represents the business KPIs and optional pod counts for a single day.
 // This will be used an input to models.
 type Daily struct {
@@ -33,14 +74,8 @@ func NewDaily(date time.Time, gmv float64, users int, marketingCost float64, feP
 	if date.IsZero() {
 		return Daily{}, ErrInvalidDate
 	}
-	if gmv < 0 {
-		return Daily{}, ErrGmvNegative
-	}
-	if users < 0 {
-		return Daily{}, ErrUsersNegative
-	}
-	if marketingCost < 0 {
-		return Daily{}, ErrMarketingCostNegative
+	if errs := ValidateKPIs(gmv, float64(users), marketingCost); errs != nil {
+		return Daily{}, errs[0]
 	}
 	return Daily{
 		Date:          date,
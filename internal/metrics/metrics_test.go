@@ -1,10 +1,12 @@
 package metrics
 
 import (
+	"math"
 	"testing"
 	"time"
 
 	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
 )
 
 func TestNewDaily_Valid(t *testing.T) {
@@ -72,3 +74,21 @@ func TestPods_Missing(t *testing.T) {
 	_, _, ok := d.Pods()
 	assert.False(t, ok)
 }
+
+func TestNewDaily_NotFinite(t *testing.T) {
+	_, err := NewDaily(time.Now(), math.NaN(), 10, 500, nil, nil)
+	assert.ErrorIs(t, err, ErrGmvNotFinite)
+	_, err = NewDaily(time.Now(), 1000, 10, math.Inf(1), nil, nil)
+	assert.ErrorIs(t, err, ErrMarketingCostNotFinite)
+}
+
+func TestValidateKPIs(t *testing.T) {
+	assert.Nil(t, ValidateKPIs(0, 0, 0))
+
+	errs := ValidateKPIs(-1, math.Inf(-1), 5)
+	require.Len(t, errs, 2)
+	assert.Equal(t, "gmv", errs[0].Field)
+	assert.ErrorIs(t, errs[0], ErrGmvNegative)
+	assert.Equal(t, "users", errs[1].Field)
+	assert.ErrorIs(t, errs[1], ErrUsersNotFinite)
+}
@@ -0,0 +1,54 @@
+package model
+
+import "time"
+
+// Describer is implemented by models that can report their fitted parameters.
+// It is optional; callers should type-assert a Model to discover support.
+type Describer interface {
+	// Describe returns the parameters of the currently trained model,
+	// or an error if the model has not been trained yet.
+	Describe() (Description, error)
+}
+
+// Description summarizes a trained model for humans and dashboards.
+type Description struct {
+	// Type names the model implementation, e.g. "linreg".
+	Type string `json:"type"`
+	// TrainingRows is the number of rows the model was fitted on.
+	TrainingRows int `json:"training_rows"`
+	// TrainedAt is when the model was fitted, in UTC.
+	TrainedAt time.Time `json:"trained_at"`
+	// FeatureStats summarizes the training features when the model
+	// implements Profiler.
+	FeatureStats *Profile `json:"feature_stats,omitempty"`
+	// FE describes the front-end pods regressor.
+	FE Regressor `json:"fe"`
+	// BE describes the back-end pods regressor.
+	BE Regressor `json:"be"`
+}
+
+// Regressor describes a single fitted regressor.
+type Regressor struct {
+	// Features lists the input feature names in model order.
+	Features []string `json:"features,omitempty"`
+	// Coefficients maps each feature name to its fitted weight, for linear regressors.
+	Coefficients map[string]float64 `json:"coefficients,omitempty"`
+	// Intercept is the constant term; for tree ensembles, the base prediction.
+	Intercept float64 `json:"intercept"`
+	// R2 is the coefficient of determination on the training data.
+	R2 float64 `json:"r2"`
+	// Formula is a human-readable form of the fitted equation, if available.
+	Formula string `json:"formula,omitempty"`
+	// Lambda is the regularization strength of penalized regressors.
+	Lambda float64 `json:"lambda,omitempty"`
+	// Trees is the number of trees of tree ensembles.
+	Trees int `json:"trees,omitempty"`
+	// OOBRMSE is the out-of-bag root mean squared error of bagged ensembles.
+	OOBRMSE float64 `json:"oob_rmse,omitempty"`
+	// Params holds fitted parameters specific to the model type, such as
+	// the smoothing factors of time-series models.
+	Params map[string]float64 `json:"params,omitempty"`
+	// Importance maps each feature to its permutation importance: the
+	// increase of the out-of-bag mean squared error when it is shuffled.
+	Importance map[string]float64 `json:"importance,omitempty"`
+}
@@ -0,0 +1,34 @@
+package model
+
+// Explainer is implemented by models that can break a prediction down into
+// per-feature contributions. It is optional; callers should type-assert a
+// Model to discover support.
+type Explainer interface {
+	// Explain predicts like Model.Predict and additionally reports how each
+	// tier's pod count was derived. The Pods fields of the result are the
+	// values Predict would return for the same features.
+	Explain(features *Features) (Explanation, error)
+}
+
+// Explanation breaks down a single FE/BE prediction.
+type Explanation struct {
+	FE TierExplanation `json:"fe"`
+	BE TierExplanation `json:"be"`
+}
+
+// TierExplanation shows how one tier's pod count was derived.
+type TierExplanation struct {
+	// Raw is the unrounded model output. It is 0 when NaNFallback is set.
+	Raw float64 `json:"raw"`
+	// Intercept is the constant term included in Raw.
+	Intercept float64 `json:"intercept"`
+	// Contributions maps each feature to coefficient × value.
+	// Raw equals Intercept plus the sum of Contributions.
+	Contributions map[string]float64 `json:"contributions"`
+	// Pods is the final pod count after rounding and guards.
+	Pods int `json:"pods"`
+	// ClampedToMin is set when the rounded value was raised to the minimum pod count.
+	ClampedToMin bool `json:"clamped_to_min"`
+	// NaNFallback is set when the model produced NaN/Inf and the minimum was used instead.
+	NaNFallback bool `json:"nan_fallback"`
+}
@@ -0,0 +1,34 @@
+package model
+
+import (
+	"errors"
+	"time"
+)
+
+// ErrBeforeHistory is returned by Forecaster.Forecast for days before the
+// first day it can forecast: days before the training data, and training
+// days used only to initialize the model, whose actuals it would echo.
+var ErrBeforeHistory = errors.New("date precedes the forecastable history")
+
+// Forecaster is implemented by models that forecast pods from the date
+// alone, using the history they were trained on, so no KPIs are needed.
+// It is optional; callers should type-assert a Model to discover support.
+type Forecaster interface {
+	// Forecast returns the pods forecast for the day of date. Days after
+	// the training data extrapolate its trend and seasonality.
+	Forecast(date time.Time) (FEPods, BEPods, error)
+}
+
+// ErrNeedsDate is returned by Predict of models that forecast by date and
+// cannot predict from features alone. Use PredictOn with a date instead.
+var ErrNeedsDate = errors.New("model forecasts by date; a date is required")
+
+// PredictOn predicts the pods of the day of date from in. A Forecaster
+// forecasts by date, since such models ignore the features; other models,
+// and a zero date, go through Predict.
+func PredictOn(m Model, date time.Time, in *Features) (FEPods, BEPods, error) {
+	if f, ok := m.(Forecaster); ok && !date.IsZero() {
+		return f.Forecast(date)
+	}
+	return m.Predict(in)
+}
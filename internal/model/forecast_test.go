@@ -0,0 +1,44 @@
+package model
+
+import (
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"github.com/thisiscetin/podpredict/internal/metrics"
+)
+
+// gmvModel predicts the GMV as FE pods.
+type gmvModel struct{}
+
+func (gmvModel) Train([]metrics.Daily) error { return nil }
+func (gmvModel) Predict(in *Features) (FEPods, BEPods, error) {
+	return FEPods(in.GMV), 1, nil
+}
+
+// dayModel is a gmvModel that forecasts the day of the month as FE pods.
+type dayModel struct{ gmvModel }
+
+func (dayModel) Forecast(date time.Time) (FEPods, BEPods, error) {
+	return FEPods(date.Day()), 2, nil
+}
+
+func TestPredictOn(t *testing.T) {
+	date := time.Date(2025, 3, 14, 0, 0, 0, 0, time.UTC)
+	in := &Features{GMV: 40}
+
+	fe, _, err := PredictOn(gmvModel{}, date, in)
+	require.NoError(t, err)
+	assert.Equal(t, FEPods(40), fe, "models without Forecaster predict from features")
+
+	fe, be, err := PredictOn(dayModel{}, date, in)
+	require.NoError(t, err)
+	assert.Equal(t, FEPods(14), fe, "Forecasters forecast by date")
+	assert.Equal(t, BEPods(2), be)
+
+	fe, _, err = PredictOn(dayModel{}, time.Time{}, in)
+	require.NoError(t, err)
+	assert.Equal(t, FEPods(40), fe, "no date falls back to Predict")
+}
@@ -0,0 +1,202 @@
+// Package forest implements a random forest: bagged regression trees that
+// split on random feature subsets. It is a robust non-parametric baseline
+// that needs no tuning, and it estimates its own error and feature
+// importance from the rows each tree did not see.
+package forest
+
+import (
+	"math"
+	"math/rand"
+
+	"github.com/thisiscetin/podpredict/internal/metrics"
+	"github.com/thisiscetin/podpredict/internal/model"
+	"github.com/thisiscetin/podpredict/internal/model/tree"
+)
+
+// Options configures a forest Model.
+// Zero values fall back to the defaults documented on each field.
+type Options struct {
+	// Trees is the number of bagged trees, default 100.
+	Trees int
+	// MaxDepth limits the depth of each tree, default 16.
+	MaxDepth int
+	// MinLeaf is the minimum number of rows in a leaf, default 2.
+	MinLeaf int
+	// MaxFeatures is the number of features drawn for every split,
+	// default a third of them (1).
+	MaxFeatures int
+	// Seed drives the bootstrap samples, feature draws and permutations.
+	// Training is deterministic for a given seed and data.
+	Seed int64
+}
+
+func (o Options) withDefaults() Options {
+	if o.Trees <= 0 {
+		o.Trees = 100
+	}
+	if o.MaxDepth <= 0 {
+		o.MaxDepth = 16
+	}
+	if o.MinLeaf <= 0 {
+		o.MinLeaf = 2
+	}
+	if o.MaxFeatures <= 0 {
+		o.MaxFeatures = max(1, len(model.FeatureNames)/3)
+	}
+	return o
+}
+
+// forestModel implements model.Model with one forest per tier.
+type forestModel struct {
+	opts Options
+	model.Latest[fit]
+}
+
+// fit is a pair of trained FE and BE forests.
+type fit struct {
+	fe, be forest
+}
+
+// forest averages its trees and records their out-of-bag estimates.
+type forest struct {
+	trees      []*tree.Node
+	base       float64 // mean target, the prediction of an empty forest
+	r2         float64
+	oobRMSE    float64
+	importance map[string]float64
+}
+
+// NewModel returns a random forest Model.
+func NewModel(opts Options) model.Model {
+	return &forestModel{opts: opts.withDefaults()}
+}
+
+// Train grows FE and BE forests on the rows with both pods.
+func (m *forestModel) Train(rows []metrics.Daily) error {
+	ts, err := model.NewTrainingSet(rows)
+	if err != nil {
+		return err
+	}
+	m.Store(fit{
+		fe: m.grow(ts.X, ts.FE, m.opts.Seed),
+		be: m.grow(ts.X, ts.BE, m.opts.Seed+1),
+	}, ts)
+	return nil
+}
+
+// grow fits Trees trees on bootstrap samples of the rows, then scores the
+// forest on each row with the trees that did not sample it.
+func (m *forestModel) grow(xs [][]float64, y []float64, seed int64) forest {
+	r := rand.New(rand.NewSource(seed))
+	n := len(xs)
+
+	f := forest{trees: make([]*tree.Node, m.opts.Trees)}
+	for _, v := range y {
+		f.base += v / float64(n)
+	}
+	inBag := make([][]bool, m.opts.Trees)
+	for t := range f.trees {
+		sample := make([]int, n)
+		inBag[t] = make([]bool, n)
+		for i := range sample {
+			sample[i] = r.Intn(n)
+			inBag[t][sample[i]] = true
+		}
+		f.trees[t] = tree.Fit(xs, y, sample, tree.Options{
+			MaxDepth:    m.opts.MaxDepth,
+			MinLeaf:     m.opts.MinLeaf,
+			MaxFeatures: m.opts.MaxFeatures,
+			Rand:        r,
+		})
+	}
+	f.r2 = model.R2(f.predict, xs, y)
+
+	oobMSE, ok := f.oobMSE(xs, y, inBag)
+	if !ok {
+		return f
+	}
+	f.oobRMSE = math.Sqrt(oobMSE)
+	f.importance = make(map[string]float64, len(model.FeatureNames))
+	for j, name := range model.FeatureNames {
+		shuffled := make([][]float64, n)
+		perm := r.Perm(n)
+		for i, x := range xs {
+			shuffled[i] = append([]float64(nil), x...)
+			shuffled[i][j] = xs[perm[i]][j]
+		}
+		permMSE, _ := f.oobMSE(shuffled, y, inBag)
+		f.importance[name] = permMSE - oobMSE
+	}
+	return f
+}
+
+// oobMSE is the mean squared error of the out-of-bag predictions: each
+// row is predicted by the trees whose bootstrap sample missed it. ok is
+// false when every tree sampled every row.
+func (f forest) oobMSE(xs [][]float64, y []float64, inBag [][]bool) (mse float64, ok bool) {
+	var scored int
+	for i, x := range xs {
+		var sum float64
+		var votes int
+		for t, tr := range f.trees {
+			if !inBag[t][i] {
+				sum += tr.Predict(x)
+				votes++
+			}
+		}
+		if votes == 0 {
+			continue
+		}
+		d := sum/float64(votes) - y[i]
+		mse += d * d
+		scored++
+	}
+	if scored == 0 {
+		return 0, false
+	}
+	return mse / float64(scored), true
+}
+
+func (f forest) predict(x []float64) float64 {
+	if len(f.trees) == 0 {
+		return f.base
+	}
+	var sum float64
+	for _, t := range f.trees {
+		sum += t.Predict(x)
+	}
+	return sum / float64(len(f.trees))
+}
+
+// Predict averages the trees of each forest and rounds to at least 1 pod.
+func (m *forestModel) Predict(f *model.Features) (model.FEPods, model.BEPods, error) {
+	cur, err := m.Load()
+	if err != nil {
+		return 0, 0, err
+	}
+	in := f.Values()
+	fe, _, _ := model.ToPods(cur.Fit.fe.predict(in))
+	be, _, _ := model.ToPods(cur.Fit.be.predict(in))
+	return model.FEPods(fe), model.BEPods(be), nil
+}
+
+// Describe reports the size, fit, out-of-bag error and permutation
+// importance of the current forests.
+func (m *forestModel) Describe() (model.Description, error) {
+	cur, err := m.Load()
+	if err != nil {
+		return model.Description{}, err
+	}
+	return cur.Describe("forest", cur.Fit.fe.describe(), cur.Fit.be.describe()), nil
+}
+
+func (f forest) describe() model.Regressor {
+	return model.Regressor{
+		Features:   model.FeatureNames,
+		Intercept:  f.base,
+		R2:         f.r2,
+		Trees:      len(f.trees),
+		OOBRMSE:    f.oobRMSE,
+		Importance: f.importance,
+	}
+}
@@ -0,0 +1,86 @@
+package forest
+
+import (
+	"math/rand"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"github.com/thisiscetin/podpredict/internal/metrics"
+	"github.com/thisiscetin/podpredict/internal/model"
+	"github.com/thisiscetin/podpredict/internal/model/modeltest"
+)
+
+// gmvRows have FE pods of GMV/100 plus noise of the given deviation;
+// Users and MarketingCost are uninformative.
+func gmvRows(n int, noise float64) []metrics.Daily {
+	r := rand.New(rand.NewSource(11))
+	var rows []metrics.Daily
+	for i := 0; i < n; i++ {
+		gmv := 1000 + r.Float64()*4000
+		fe := int(gmv/100 + r.NormFloat64()*noise)
+		rows = append(rows, modeltest.Day(i, gmv, r.Intn(1000), r.Float64()*500, fe, fe/2+1))
+	}
+	return rows
+}
+
+func TestForest_ReportsOOBErrorAndImportance(t *testing.T) {
+	m := NewModel(Options{Seed: 1})
+	_, _, err := m.Predict(&model.Features{})
+	require.Error(t, err, "predict before train must fail")
+	require.Error(t, m.Train(nil))
+
+	require.NoError(t, m.Train(gmvRows(150, 2)))
+	d, err := m.(model.Describer).Describe()
+	require.NoError(t, err)
+
+	assert.Equal(t, "forest", d.Type)
+	assert.Equal(t, 150, d.TrainingRows)
+	assert.Equal(t, 100, d.FE.Trees)
+	require.NotNil(t, d.FeatureStats)
+
+	// The out-of-bag error approaches the noise, well below the spread of
+	// the target (std ≈ 11.5 pods).
+	assert.Greater(t, d.FE.OOBRMSE, 1.0)
+	assert.Less(t, d.FE.OOBRMSE, 5.0)
+	assert.Greater(t, d.FE.R2, 0.9)
+
+	require.Len(t, d.FE.Importance, 3)
+	assert.Greater(t, d.FE.Importance["GMV"], 10*d.FE.Importance["Users"])
+	assert.Greater(t, d.FE.Importance["GMV"], 10*d.FE.Importance["MarketingCost"])
+
+	fe, _, err := m.Predict(&model.Features{GMV: 3000, Users: 500, MarketingCost: 250})
+	require.NoError(t, err)
+	assert.InDelta(t, 30, int(fe), 3)
+}
+
+func TestForest_DeterministicForSeed(t *testing.T) {
+	rows := gmvRows(60, 3)
+	describe := func(seed int64) model.Description {
+		m := NewModel(Options{Trees: 20, Seed: seed})
+		require.NoError(t, m.Train(rows))
+		d, err := m.(model.Describer).Describe()
+		require.NoError(t, err)
+		d.TrainedAt = time.Time{}
+		return d
+	}
+	assert.Equal(t, describe(7), describe(7))
+	assert.NotEqual(t, describe(7).FE.OOBRMSE, describe(8).FE.OOBRMSE)
+}
+
+func TestForest_SingleRowHasNoOOBEstimate(t *testing.T) {
+	m := NewModel(Options{Trees: 5})
+	require.NoError(t, m.Train([]metrics.Daily{modeltest.Day(0, 100, 10, 5, 4, 2)}))
+
+	fe, be, err := m.Predict(&model.Features{GMV: 1e6})
+	require.NoError(t, err)
+	assert.Equal(t, model.FEPods(4), fe)
+	assert.Equal(t, model.BEPods(2), be)
+
+	d, err := m.(model.Describer).Describe()
+	require.NoError(t, err)
+	assert.Zero(t, d.FE.OOBRMSE)
+	assert.Nil(t, d.FE.Importance)
+}
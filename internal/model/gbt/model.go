@@ -0,0 +1,202 @@
+// Package gbt implements gradient-boosted regression trees, which capture
+// non-linear effects such as a step change in pods once marketing spend
+// crosses a threshold.
+package gbt
+
+import (
+	"math"
+	"math/rand"
+
+	"github.com/thisiscetin/podpredict/internal/metrics"
+	"github.com/thisiscetin/podpredict/internal/model"
+	"github.com/thisiscetin/podpredict/internal/model/tree"
+)
+
+// Options configures a gbt Model.
+// Zero values fall back to the defaults documented on each field.
+type Options struct {
+	// Trees is the maximum number of boosting rounds, default 200.
+	Trees int
+	// MaxDepth is the depth of each tree, default 3.
+	MaxDepth int
+	// MinLeaf is the minimum number of rows in a leaf, default 2.
+	MinLeaf int
+	// LearningRate shrinks the contribution of each tree, default 0.1.
+	LearningRate float64
+	// ValidationFraction is the share of rows held out for early
+	// stopping, default 0.2.
+	ValidationFraction float64
+	// EarlyStoppingRounds stops boosting after this many rounds without
+	// a lower validation error and keeps the best round, default 20.
+	// Negative disables early stopping, and every row is trained on.
+	EarlyStoppingRounds int
+	// Seed drives the validation split. Training is deterministic for a
+	// given seed and data.
+	Seed int64
+}
+
+func (o Options) withDefaults() Options {
+	if o.Trees <= 0 {
+		o.Trees = 200
+	}
+	if o.MaxDepth <= 0 {
+		o.MaxDepth = 3
+	}
+	if o.MinLeaf <= 0 {
+		o.MinLeaf = 2
+	}
+	if o.LearningRate <= 0 {
+		o.LearningRate = 0.1
+	}
+	if o.ValidationFraction <= 0 || o.ValidationFraction >= 1 {
+		o.ValidationFraction = 0.2
+	}
+	if o.EarlyStoppingRounds == 0 {
+		o.EarlyStoppingRounds = 20
+	}
+	return o
+}
+
+// gbtModel implements model.Model with one boosted ensemble per tier.
+type gbtModel struct {
+	opts Options
+	model.Latest[fit]
+}
+
+// fit is a pair of trained FE and BE ensembles.
+type fit struct {
+	fe, be ensemble
+}
+
+// ensemble predicts base + rate × the sum of its trees.
+type ensemble struct {
+	base  float64
+	rate  float64
+	trees []*tree.Node
+	r2    float64
+}
+
+// NewModel returns a gradient-boosted trees Model.
+func NewModel(opts Options) model.Model {
+	return &gbtModel{opts: opts.withDefaults()}
+}
+
+// Train boosts FE and BE ensembles on the rows with both pods. With early
+// stopping, the same seeded split holds out validation rows for both.
+func (m *gbtModel) Train(rows []metrics.Daily) error {
+	ts, err := model.NewTrainingSet(rows)
+	if err != nil {
+		return err
+	}
+
+	train, valid := m.split(len(ts.X))
+	fe := m.boost(ts.X, ts.FE, train, valid)
+	be := m.boost(ts.X, ts.BE, train, valid)
+	fe.r2 = model.R2(fe.predict, ts.X, ts.FE)
+	be.r2 = model.R2(be.predict, ts.X, ts.BE)
+	m.Store(fit{fe: fe, be: be}, ts)
+	return nil
+}
+
+// split shuffles the n row indices with the seed and holds out the
+// validation fraction, keeping at least two training rows. valid is empty
+// when early stopping is disabled or there are too few rows.
+func (m *gbtModel) split(n int) (train, valid []int) {
+	idx := rand.New(rand.NewSource(m.opts.Seed)).Perm(n)
+	k := int(math.Round(m.opts.ValidationFraction * float64(n)))
+	if m.opts.EarlyStoppingRounds < 0 || k < 1 || n-k < 2 {
+		return idx, nil
+	}
+	return idx[k:], idx[:k]
+}
+
+// boost fits trees to the residuals of the train rows. With validation
+// rows it stops after EarlyStoppingRounds rounds without improvement and
+// keeps the rounds up to the lowest validation error.
+func (m *gbtModel) boost(xs [][]float64, y []float64, train, valid []int) ensemble {
+	e := ensemble{rate: m.opts.LearningRate}
+	for _, i := range train {
+		e.base += y[i] / float64(len(train))
+	}
+
+	pred := make([]float64, len(xs))
+	for i := range pred {
+		pred[i] = e.base
+	}
+	resid := make([]float64, len(xs))
+	best, bestErr := 0, mse(pred, y, valid)
+	for round := 1; round <= m.opts.Trees; round++ {
+		for _, i := range train {
+			resid[i] = y[i] - pred[i]
+		}
+		t := tree.Fit(xs, resid, train, tree.Options{MaxDepth: m.opts.MaxDepth, MinLeaf: m.opts.MinLeaf})
+		e.trees = append(e.trees, t)
+		for i, x := range xs {
+			pred[i] += e.rate * t.Predict(x)
+		}
+
+		if valid == nil {
+			continue
+		}
+		if err := mse(pred, y, valid); err < bestErr {
+			best, bestErr = round, err
+		} else if round-best >= m.opts.EarlyStoppingRounds {
+			break
+		}
+	}
+	if valid != nil {
+		e.trees = e.trees[:best]
+	}
+	return e
+}
+
+// mse is the mean squared error of pred on rows idx, 0 when idx is empty.
+func mse(pred, y []float64, idx []int) float64 {
+	var sum float64
+	for _, i := range idx {
+		d := pred[i] - y[i]
+		sum += d * d
+	}
+	if len(idx) == 0 {
+		return 0
+	}
+	return sum / float64(len(idx))
+}
+
+func (e ensemble) predict(x []float64) float64 {
+	v := e.base
+	for _, t := range e.trees {
+		v += e.rate * t.Predict(x)
+	}
+	return v
+}
+
+// Predict sums the trees of each ensemble and rounds to at least 1 pod.
+func (m *gbtModel) Predict(f *model.Features) (model.FEPods, model.BEPods, error) {
+	cur, err := m.Load()
+	if err != nil {
+		return 0, 0, err
+	}
+	in := f.Values()
+	fe, _, _ := model.ToPods(cur.Fit.fe.predict(in))
+	be, _, _ := model.ToPods(cur.Fit.be.predict(in))
+	return model.FEPods(fe), model.BEPods(be), nil
+}
+
+// Describe reports the size and fit of the current ensembles.
+func (m *gbtModel) Describe() (model.Description, error) {
+	cur, err := m.Load()
+	if err != nil {
+		return model.Description{}, err
+	}
+	return cur.Describe("gbt", cur.Fit.fe.describe(), cur.Fit.be.describe()), nil
+}
+
+func (e ensemble) describe() model.Regressor {
+	return model.Regressor{
+		Features:  model.FeatureNames,
+		Intercept: e.base,
+		R2:        e.r2,
+		Trees:     len(e.trees),
+	}
+}
@@ -0,0 +1,109 @@
+package gbt
+
+import (
+	"math/rand"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"github.com/thisiscetin/podpredict/internal/metrics"
+	"github.com/thisiscetin/podpredict/internal/model"
+	"github.com/thisiscetin/podpredict/internal/model/modeltest"
+)
+
+// campaignRows have 10 FE pods, or 30 once marketing spend exceeds 500,
+// plus noise when noisy is set.
+func campaignRows(n int, noisy bool) []metrics.Daily {
+	r := rand.New(rand.NewSource(3))
+	var rows []metrics.Daily
+	for i := 0; i < n; i++ {
+		mc := r.Float64() * 1000
+		fe := 10
+		if mc > 500 {
+			fe = 30
+		}
+		if noisy {
+			fe += int(r.NormFloat64() * 3)
+		}
+		rows = append(rows, modeltest.Day(i, 1000+r.Float64()*100, 100+r.Intn(10), mc, fe, fe/2))
+	}
+	return rows
+}
+
+func TestGBT_LearnsStepChange(t *testing.T) {
+	m := NewModel(Options{})
+	_, _, err := m.Predict(&model.Features{})
+	require.Error(t, err, "predict before train must fail")
+	require.Error(t, m.Train(nil))
+
+	require.NoError(t, m.Train(campaignRows(100, false)))
+	for mc, want := range map[float64]model.FEPods{100: 10, 450: 10, 550: 30, 900: 30} {
+		fe, be, err := m.Predict(&model.Features{GMV: 1050, Users: 105, MarketingCost: mc})
+		require.NoError(t, err)
+		assert.Equal(t, want, fe, "marketing cost %v", mc)
+		assert.Equal(t, model.BEPods(want/2), be, "marketing cost %v", mc)
+	}
+
+	d, err := m.(model.Describer).Describe()
+	require.NoError(t, err)
+	assert.Equal(t, "gbt", d.Type)
+	assert.Equal(t, 100, d.TrainingRows)
+	assert.Greater(t, d.FE.R2, 0.99)
+	assert.Positive(t, d.FE.Trees)
+	require.NotNil(t, d.FeatureStats)
+}
+
+func TestGBT_DeterministicForSeed(t *testing.T) {
+	rows := campaignRows(80, true)
+	in := &model.Features{GMV: 1020, Users: 104, MarketingCost: 480}
+
+	describe := func(seed int64) model.Description {
+		m := NewModel(Options{Seed: seed})
+		require.NoError(t, m.Train(rows))
+		d, err := m.(model.Describer).Describe()
+		require.NoError(t, err)
+		d.TrainedAt = time.Time{}
+		return d
+	}
+	assert.Equal(t, describe(42), describe(42))
+
+	a, b := NewModel(Options{Seed: 42}), NewModel(Options{Seed: 42})
+	require.NoError(t, a.Train(rows))
+	require.NoError(t, b.Train(rows))
+	feA, beA, _ := a.Predict(in)
+	feB, beB, _ := b.Predict(in)
+	assert.Equal(t, feA, feB)
+	assert.Equal(t, beA, beB)
+}
+
+func TestGBT_EarlyStopping(t *testing.T) {
+	rows := campaignRows(100, true)
+
+	stopped := NewModel(Options{Trees: 500, EarlyStoppingRounds: 10, Seed: 1})
+	require.NoError(t, stopped.Train(rows))
+	d, err := stopped.(model.Describer).Describe()
+	require.NoError(t, err)
+	assert.Less(t, d.FE.Trees, 500, "noise stops boosting early")
+
+	full := NewModel(Options{Trees: 50, EarlyStoppingRounds: -1})
+	require.NoError(t, full.Train(rows))
+	d, err = full.(model.Describer).Describe()
+	require.NoError(t, err)
+	assert.Equal(t, 50, d.FE.Trees)
+	assert.Equal(t, 50, d.BE.Trees)
+}
+
+func TestSplit(t *testing.T) {
+	m := NewModel(Options{Seed: 9}).(*gbtModel)
+	train, valid := m.split(10)
+	assert.Len(t, train, 8)
+	assert.Len(t, valid, 2)
+	assert.ElementsMatch(t, []int{0, 1, 2, 3, 4, 5, 6, 7, 8, 9}, append(train, valid...))
+
+	// Too few rows to hold any out.
+	train, valid = m.split(2)
+	assert.Len(t, train, 2)
+	assert.Empty(t, valid)
+}
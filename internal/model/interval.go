@@ -0,0 +1,90 @@
+package model
+
+import (
+	"fmt"
+	"math"
+	"strconv"
+	"strings"
+)
+
+// IntervalPredictor is implemented by models that can quantify the
+// uncertainty of a prediction. It is optional; callers should type-assert a
+// Model to discover support.
+type IntervalPredictor interface {
+	// PredictInterval returns the point estimate and the two-sided prediction
+	// interval holding a new observation with probability confidence, which
+	// must be in (0, 1).
+	PredictInterval(features *Features, confidence float64) (Interval, error)
+}
+
+// Bound selects which value of an Interval becomes the recommended pod count.
+type Bound string
+
+const (
+	BoundPoint Bound = "point"
+	BoundLower Bound = "lower"
+	BoundUpper Bound = "upper"
+)
+
+// ParseBound parses "point", "lower" or "upper".
+func ParseBound(s string) (Bound, error) {
+	switch b := Bound(strings.ToLower(s)); b {
+	case BoundPoint, BoundLower, BoundUpper:
+		return b, nil
+	default:
+		return "", fmt.Errorf("invalid bound %q: want point, lower or upper", s)
+	}
+}
+
+// ParseConfidence parses a confidence level written as a percentile such as
+// "p90", a percentage such as "90", or a fraction such as "0.9".
+func ParseConfidence(s string) (float64, error) {
+	v, err := strconv.ParseFloat(strings.TrimPrefix(strings.ToLower(s), "p"), 64)
+	if err != nil {
+		return 0, fmt.Errorf("invalid confidence %q", s)
+	}
+	if v >= 1 {
+		v /= 100
+	}
+	if v <= 0 || v >= 1 {
+		return 0, fmt.Errorf("invalid confidence %q: must be between 0 and 1 (exclusive)", s)
+	}
+	return v, nil
+}
+
+// Interval holds FE/BE prediction intervals at a given confidence.
+type Interval struct {
+	// Confidence is the probability mass inside [Lower, Upper], e.g. 0.9.
+	Confidence float64 `json:"confidence"`
+	// Bound records which value was used as the recommended pod count.
+	// It is set by the caller, not by the model.
+	Bound Bound  `json:"bound,omitempty"`
+	FE    Bounds `json:"fe"`
+	BE    Bounds `json:"be"`
+}
+
+// Bounds is a point estimate with its prediction interval, in raw (unrounded) pods.
+type Bounds struct {
+	Point float64 `json:"point"`
+	Lower float64 `json:"lower"`
+	Upper float64 `json:"upper"`
+}
+
+// Pods converts the selected bound into a pod count of at least 1.
+// Lower bounds round down and upper bounds round up so that the chosen
+// confidence is never silently weakened; the point estimate is rounded.
+func (b Bounds) Pods(which Bound) int {
+	var v float64
+	switch which {
+	case BoundLower:
+		v = math.Floor(b.Lower)
+	case BoundUpper:
+		v = math.Ceil(b.Upper)
+	default:
+		v = math.Round(b.Point)
+	}
+	if math.IsNaN(v) || math.IsInf(v, 0) || v < 1 {
+		return 1
+	}
+	return int(v)
+}
@@ -0,0 +1,42 @@
+package model
+
+import (
+	"math"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestParseConfidence(t *testing.T) {
+	for in, want := range map[string]float64{"p90": 0.9, "P99": 0.99, "50": 0.5, "0.95": 0.95} {
+		got, err := ParseConfidence(in)
+		require.NoError(t, err, in)
+		assert.InDelta(t, want, got, 1e-12, in)
+	}
+	for _, in := range []string{"", "p", "abc", "0", "100", "-0.5", "p100"} {
+		_, err := ParseConfidence(in)
+		assert.Error(t, err, in)
+	}
+}
+
+func TestParseBound(t *testing.T) {
+	b, err := ParseBound("Upper")
+	require.NoError(t, err)
+	assert.Equal(t, BoundUpper, b)
+
+	_, err = ParseBound("middle")
+	assert.Error(t, err)
+}
+
+func TestBounds_Pods(t *testing.T) {
+	b := Bounds{Point: 4.4, Lower: 2.7, Upper: 6.1}
+	assert.Equal(t, 4, b.Pods(BoundPoint))
+	assert.Equal(t, 2, b.Pods(BoundLower))
+	assert.Equal(t, 7, b.Pods(BoundUpper))
+
+	neg := Bounds{Point: 0.2, Lower: -3, Upper: math.NaN()}
+	assert.Equal(t, 1, neg.Pods(BoundPoint))
+	assert.Equal(t, 1, neg.Pods(BoundLower))
+	assert.Equal(t, 1, neg.Pods(BoundUpper))
+}
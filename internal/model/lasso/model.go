@@ -0,0 +1,90 @@
+// Package lasso implements lasso regression: least squares with an L1
+// penalty, which shrinks the coefficients of uninformative KPIs to zero.
+package lasso
+
+import (
+	"math"
+
+	"github.com/thisiscetin/podpredict/internal/model"
+	"github.com/thisiscetin/podpredict/internal/model/penalized"
+)
+
+// Options configures a lasso Model.
+type Options struct {
+	// Lambda is the L1 regularization strength on standardized features.
+	// 0 selects it by cross-validation.
+	Lambda float64
+	// Folds is the number of cross-validation folds; penalized.DefaultFolds if 0.
+	Folds int
+}
+
+// NewModel returns a lasso regression Model.
+func NewModel(opts Options) model.Model {
+	return penalized.NewModel(penalized.Options{
+		Type:   "lasso",
+		Solve:  Solve,
+		Lambda: opts.Lambda,
+		Folds:  opts.Folds,
+	})
+}
+
+// Coordinate descent limits.
+const (
+	maxIterations = 10000
+	tolerance     = 1e-9
+)
+
+// Solve minimizes ||y − Xb||²/(2n) + lambda·||b||₁ by cyclic coordinate
+// descent with soft-thresholding. It is a penalized.Solver.
+func Solve(x [][]float64, y []float64, lambda float64) ([]float64, error) {
+	n := float64(len(x))
+	p := len(x[0])
+
+	// Column norms ||x_j||²/n: 1 for standardized columns, 0 for constant ones.
+	norms := make([]float64, p)
+	for j := range norms {
+		for i := range x {
+			norms[j] += x[i][j] * x[i][j] / n
+		}
+	}
+
+	b := make([]float64, p)
+	resid := append([]float64(nil), y...)
+	for iter := 0; iter < maxIterations; iter++ {
+		var maxDelta float64
+		for j := 0; j < p; j++ {
+			if norms[j] == 0 {
+				continue
+			}
+			// rho = x_jᵀ(resid + x_j·b_j)/n
+			var rho float64
+			for i := range x {
+				rho += x[i][j] * resid[i] / n
+			}
+			rho += norms[j] * b[j]
+
+			next := softThreshold(rho, lambda) / norms[j]
+			if delta := next - b[j]; delta != 0 {
+				for i := range x {
+					resid[i] -= x[i][j] * delta
+				}
+				maxDelta = math.Max(maxDelta, math.Abs(delta))
+				b[j] = next
+			}
+		}
+		if maxDelta < tolerance {
+			break
+		}
+	}
+	return b, nil
+}
+
+func softThreshold(v, lambda float64) float64 {
+	switch {
+	case v > lambda:
+		return v - lambda
+	case v < -lambda:
+		return v + lambda
+	}
+	return 0
+}
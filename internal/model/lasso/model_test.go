@@ -0,0 +1,76 @@
+package lasso
+
+import (
+	"math"
+	"math/rand"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"github.com/thisiscetin/podpredict/internal/metrics"
+	"github.com/thisiscetin/podpredict/internal/model"
+	"github.com/thisiscetin/podpredict/internal/model/modeltest"
+)
+
+func TestLasso_DropsUninformativeFeatures(t *testing.T) {
+	// Pods depend on GMV only; Users and MarketingCost are noise.
+	r := rand.New(rand.NewSource(7))
+	var rows []metrics.Daily
+	for i := 0; i < 80; i++ {
+		gmv := 1000 + r.Float64()*1000
+		pods := int(gmv/50 + r.NormFloat64())
+		rows = append(rows, modeltest.Day(i, gmv, r.Intn(500), r.Float64()*100, pods, pods/2))
+	}
+
+	// Cross-validation keeps the noise features' effect negligible.
+	m := NewModel(Options{})
+	require.NoError(t, m.Train(rows))
+	d, err := m.(model.Describer).Describe()
+	require.NoError(t, err)
+	assert.Equal(t, "lasso", d.Type)
+	assert.Positive(t, d.FE.Lambda)
+	assert.InDelta(t, 0.02, d.FE.Coefficients["GMV"], 0.002)
+	assert.Less(t, math.Abs(d.FE.Coefficients["Users"])*500, 2.0)
+	assert.Less(t, math.Abs(d.FE.Coefficients["MarketingCost"])*100, 2.0)
+	assert.Greater(t, d.FE.R2, 0.9)
+
+	// A moderate fixed lambda zeroes them exactly.
+	m = NewModel(Options{Lambda: 1})
+	require.NoError(t, m.Train(rows))
+	d, err = m.(model.Describer).Describe()
+	require.NoError(t, err)
+	assert.Positive(t, d.FE.Coefficients["GMV"])
+	assert.Equal(t, 0.0, d.FE.Coefficients["Users"])
+	assert.Equal(t, 0.0, d.FE.Coefficients["MarketingCost"])
+}
+
+func TestLasso_LargeLambdaPredictsTheMean(t *testing.T) {
+	rows := []metrics.Daily{
+		modeltest.Day(0, 10, 1, 1, 2, 4),
+		modeltest.Day(1, 20, 2, 3, 4, 8),
+		modeltest.Day(2, 30, 3, 2, 6, 12),
+	}
+	m := NewModel(Options{Lambda: 1e6})
+	require.NoError(t, m.Train(rows))
+
+	fe, be, err := m.Predict(&model.Features{GMV: 500, Users: 50, MarketingCost: 9})
+	require.NoError(t, err)
+	assert.Equal(t, model.FEPods(4), fe)
+	assert.Equal(t, model.BEPods(8), be)
+}
+
+func TestSolve_MatchesLeastSquaresWithoutPenalty(t *testing.T) {
+	// Standardized, uncorrelated columns: OLS coefficients are x_jᵀy/n.
+	x := [][]float64{{1, 1}, {1, -1}, {-1, 1}, {-1, -1}}
+	y := []float64{3, 1, -1, -3}
+	b, err := Solve(x, y, 0)
+	require.NoError(t, err)
+	assert.InDelta(t, 2, b[0], 1e-9)
+	assert.InDelta(t, 1, b[1], 1e-9)
+
+	b, err = Solve(x, y, 1.5)
+	require.NoError(t, err)
+	assert.InDelta(t, 0.5, b[0], 1e-9)
+	assert.Equal(t, 0.0, b[1])
+}
@@ -0,0 +1,138 @@
+package linreg
+
+import (
+	"errors"
+	"math"
+
+	"github.com/sajari/regression"
+	"github.com/thisiscetin/podpredict/internal/model"
+	"gonum.org/v1/gonum/mat"
+	"gonum.org/v1/gonum/stat/distuv"
+)
+
+// uncertainty holds what is needed to turn a point estimate into an OLS
+// prediction interval. FE and BE share the design matrix, so they share R
+// and the degrees of freedom but have their own residual variance.
+type uncertainty struct {
+	r     [][]float64 // p×p upper-triangular R of the design matrix X = QR
+	df    int         // residual degrees of freedom, n - p
+	feVar float64     // FE residual variance, SSE / df
+	beVar float64     // BE residual variance, SSE / df
+}
+
+// newUncertainty derives interval statistics from the training inputs xs,
+// the observed FE/BE pods and the fitted regressors. It returns nil when there
+// are too few rows or the design matrix is rank-deficient; intervals are then
+// unavailable.
+func newUncertainty(xs [][]float64, feY, beY []float64, fe, be *regression.Regression) *uncertainty {
+	n, p := len(xs), len(xs[0])+1 // +1 for the intercept column
+	if n <= p {
+		return nil
+	}
+
+	x := mat.NewDense(n, p, nil)
+	for i, row := range xs {
+		x.Set(i, 0, 1)
+		for j, v := range row {
+			x.Set(i, j+1, v)
+		}
+	}
+	var qr mat.QR
+	qr.Factorize(x)
+	var full mat.Dense
+	qr.RTo(&full)
+
+	r := make([][]float64, p)
+	for i := range r {
+		r[i] = make([]float64, p)
+		for j := i; j < p; j++ {
+			r[i][j] = full.At(i, j)
+		}
+		// Treat near-zero pivots relative to the column scale as rank deficiency.
+		if math.Abs(r[i][i]) <= 1e-10*(1+math.Abs(r[0][0])) {
+			return nil
+		}
+	}
+
+	df := n - p
+	return &uncertainty{
+		r:     r,
+		df:    df,
+		feVar: sse(fe, xs, feY) / float64(df),
+		beVar: sse(be, xs, beY) / float64(df),
+	}
+}
+
+// sse returns the sum of squared residuals of reg on xs against observed ys.
+func sse(reg *regression.Regression, xs [][]float64, ys []float64) float64 {
+	var sum float64
+	for i, x := range xs {
+		pred, err := reg.Predict(x)
+		if err != nil {
+			continue
+		}
+		d := ys[i] - pred
+		sum += d * d
+	}
+	return sum
+}
+
+// leverage returns x0ᵀ(XᵀX)⁻¹x0 for the augmented input [1, in...],
+// computed as ‖R⁻ᵀx0‖² by forward substitution.
+func (u *uncertainty) leverage(in []float64) float64 {
+	p := len(u.r)
+	x0 := make([]float64, p)
+	x0[0] = 1
+	copy(x0[1:], in)
+
+	z := make([]float64, p)
+	var sum float64
+	for i := 0; i < p; i++ {
+		v := x0[i]
+		for j := 0; j < i; j++ {
+			v -= u.r[j][i] * z[j]
+		}
+		z[i] = v / u.r[i][i]
+		sum += z[i] * z[i]
+	}
+	return sum
+}
+
+// bounds returns the point estimate and its prediction interval for one tier.
+func (u *uncertainty) bounds(point, variance, lev, confidence float64) model.Bounds {
+	t := distuv.StudentsT{Mu: 0, Sigma: 1, Nu: float64(u.df)}.Quantile((1 + confidence) / 2)
+	half := t * math.Sqrt(variance*(1+lev))
+	return model.Bounds{Point: finite(point), Lower: finite(point - half), Upper: finite(point + half)}
+}
+
+// PredictInterval returns FE/BE point estimates with OLS prediction
+// intervals at the given confidence, derived from the residual variance.
+func (m *linearModel) PredictInterval(f *model.Features, confidence float64) (model.Interval, error) {
+	if confidence <= 0 || confidence >= 1 {
+		return model.Interval{}, errors.New("confidence must be between 0 and 1 (exclusive)")
+	}
+	cur := m.cur.Load()
+	if cur == nil {
+		return model.Interval{}, errors.New("model not trained")
+	}
+	if cur.unc == nil {
+		return model.Interval{}, errors.New("prediction intervals unavailable: not enough independent training rows")
+	}
+	in := []float64{f.GMV, f.Users, f.MarketingCost}
+
+	fe, err := cur.fe.Predict(in)
+	if err != nil {
+		return model.Interval{}, err
+	}
+	be, err := cur.be.Predict(in)
+	if err != nil {
+		return model.Interval{}, err
+	}
+
+	lev := cur.unc.leverage(in)
+	return model.Interval{
+		Confidence: confidence,
+		FE:         cur.unc.bounds(fe, cur.unc.feVar, lev, confidence),
+		BE:         cur.unc.bounds(be, cur.unc.beVar, lev, confidence),
+	}, nil
+}
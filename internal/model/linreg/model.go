@@ -4,6 +4,7 @@ import (
 	"errors"
 	"math"
 	"sync/atomic"
+	"time"
 
 	"github.com/sajari/regression"
 	"github.com/thisiscetin/podpredict/internal/metrics"
@@ -12,18 +13,26 @@ import (
 
 // linearModel implements Model using github.com/sajari/regression.
 type linearModel struct {
+	// cur holds the regressors of the last successful Train(). It is nil
+	// until then, which keeps Predict() unavailable. Train() builds a new fit
+	// off to the side and swaps it in, so Predict() never sees a partial one.
+	cur atomic.Pointer[fit]
+}
+
+// fit is an immutable pair of trained FE and BE regressors.
+type fit struct {
 	fe *regression.Regression
 	be *regression.Regression
-	// trained ensures Predict() is only available after a successful Train()
-	trained atomic.Bool
+
+	rows      int           // number of training rows
+	trainedAt time.Time     // when Train() finished
+	unc       *uncertainty  // nil when prediction intervals are unavailable
+	profile   model.Profile // of the training features
 }
 
 // NewModel returns a Model backed by sajari/regression.
 func NewModel() model.Model {
-	return &linearModel{
-		fe: &regression.Regression{},
-		be: &regression.Regression{},
-	}
+	return &linearModel{}
 }
 
 // Train builds two independent linear regressors for FE and BE pods.
@@ -43,7 +52,8 @@ func (m *linearModel) Train(rows []metrics.Daily) error {
 	be.SetVar(1, "Users")
 	be.SetVar(2, "MarketingCost")
 
-	n := 0
+	var xs [][]float64
+	var feY, beY []float64
 	for _, d := range rows {
 		if !d.HasPods() {
 			continue
@@ -53,8 +63,11 @@ func (m *linearModel) Train(rows []metrics.Daily) error {
 
 		fe.Train(regression.DataPoint(float64(fePods), x))
 		be.Train(regression.DataPoint(float64(bePods), x))
-		n++
+		xs = append(xs, x)
+		feY = append(feY, float64(fePods))
+		beY = append(beY, float64(bePods))
 	}
+	n := len(xs)
 	if n == 0 {
 		return errors.New("no valid rows with FE/BE pods")
 	}
@@ -66,45 +79,127 @@ func (m *linearModel) Train(rows []metrics.Daily) error {
 		return err
 	}
 
-	m.fe = fe
-	m.be = be
-	m.trained.Store(true)
+	m.cur.Store(&fit{
+		fe:        fe,
+		be:        be,
+		rows:      n,
+		trainedAt: time.Now().UTC(),
+		unc:       newUncertainty(xs, feY, beY, fe, be),
+		profile:   model.NewProfile(rows),
+	})
 	return nil
 }
 
+// Describe reports the coefficients and fit statistics of the current FE and BE regressors.
+func (m *linearModel) Describe() (model.Description, error) {
+	cur := m.cur.Load()
+	if cur == nil {
+		return model.Description{}, errors.New("model not trained")
+	}
+	return model.Description{
+		Type:         "linreg",
+		TrainingRows: cur.rows,
+		TrainedAt:    cur.trainedAt,
+		FeatureStats: &cur.profile,
+		FE:           describe(cur.fe),
+		BE:           describe(cur.be),
+	}, nil
+}
+
+// Profile reports the per-feature statistics of the current training set.
+func (m *linearModel) Profile() (model.Profile, error) {
+	cur := m.cur.Load()
+	if cur == nil {
+		return model.Profile{}, errors.New("model not trained")
+	}
+	return cur.profile, nil
+}
+
+// describe converts a trained regression into a model.Regressor.
+func describe(r *regression.Regression) model.Regressor {
+	coeffs := r.GetCoeffs() // [intercept, GMV, Users, MarketingCost]
+	out := model.Regressor{
+		Features:     make([]string, 0, len(coeffs)-1),
+		Coefficients: make(map[string]float64, len(coeffs)-1),
+		Intercept:    coeffs[0],
+		R2:           finite(r.R2),
+		Formula:      r.Formula,
+	}
+	for i := 1; i < len(coeffs); i++ {
+		name := r.GetVar(i - 1)
+		out.Features = append(out.Features, name)
+		out.Coefficients[name] = coeffs[i]
+	}
+	return out
+}
+
+// finite maps NaN/Inf to 0, e.g. R² of a constant target, so results stay JSON-encodable.
+func finite(v float64) float64 {
+	if math.IsNaN(v) || math.IsInf(v, 0) {
+		return 0
+	}
+	return v
+}
+
 // Predict returns rounded FE/BE pod counts for the supplied features.
 // Guarantees a minimum of 1 pod for both FE and BE.
 func (m *linearModel) Predict(f *model.Features) (model.FEPods, model.BEPods, error) {
-	if !m.trained.Load() {
+	cur := m.cur.Load()
+	if cur == nil {
 		return 0, 0, errors.New("model not trained")
 	}
 	in := []float64{f.GMV, f.Users, f.MarketingCost}
 
-	fe, err := m.fe.Predict(in)
+	fe, err := cur.fe.Predict(in)
 	if err != nil {
 		return 0, 0, err
 	}
-	be, err := m.be.Predict(in)
+	be, err := cur.be.Predict(in)
 	if err != nil {
 		return 0, 0, err
 	}
 
-	feInt := clampMinInt(safeRound(fe), 1)
-	beInt := clampMinInt(safeRound(be), 1)
+	feInt, _, _ := model.ToPods(fe)
+	beInt, _, _ := model.ToPods(be)
 	return model.FEPods(feInt), model.BEPods(beInt), nil
 }
 
-// safeRound handles NaN/Inf defensively before rounding.
-func safeRound(v float64) int {
-	if math.IsNaN(v) || math.IsInf(v, 0) {
-		return 1 // fallback to minimum if solver returns invalid
+// Explain predicts like Predict and breaks each tier down into the
+// intercept and per-feature contributions (coefficient × value).
+func (m *linearModel) Explain(f *model.Features) (model.Explanation, error) {
+	cur := m.cur.Load()
+	if cur == nil {
+		return model.Explanation{}, errors.New("model not trained")
+	}
+	in := []float64{f.GMV, f.Users, f.MarketingCost}
+
+	fe, err := explain(cur.fe, in)
+	if err != nil {
+		return model.Explanation{}, err
 	}
-	return int(math.Round(v))
+	be, err := explain(cur.be, in)
+	if err != nil {
+		return model.Explanation{}, err
+	}
+	return model.Explanation{FE: fe, BE: be}, nil
 }
 
-func clampMinInt(v, min int) int {
-	if v < min {
-		return min
+// explain evaluates a single regressor on in and records how the pod count was derived.
+func explain(r *regression.Regression, in []float64) (model.TierExplanation, error) {
+	raw, err := r.Predict(in)
+	if err != nil {
+		return model.TierExplanation{}, err
 	}
-	return v
+	coeffs := r.GetCoeffs() // [intercept, GMV, Users, MarketingCost]
+
+	out := model.TierExplanation{
+		Raw:           finite(raw),
+		Intercept:     coeffs[0],
+		Contributions: make(map[string]float64, len(in)),
+	}
+	for i, v := range in {
+		out.Contributions[r.GetVar(i)] = finite(coeffs[i+1] * v)
+	}
+	out.Pods, out.NaNFallback, out.ClampedToMin = model.ToPods(raw)
+	return out, nil
 }
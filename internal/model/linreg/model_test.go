@@ -1,6 +1,7 @@
 package linreg
 
 import (
+	"sync"
 	"testing"
 	"time"
 
@@ -142,7 +143,7 @@ func TestLinearModel_InternalCoefficients_AreInExpectedOrder(t *testing.T) {
 	}
 
 	// Train FE only; BE ignored.
-	m := &linearModel{fe: &regression.Regression{}, be: &regression.Regression{}}
+	m := &fit{fe: &regression.Regression{}, be: &regression.Regression{}}
 	m.fe.SetObserved("FEPods")
 	m.fe.SetVar(0, "GMV")
 	m.fe.SetVar(1, "Users")
@@ -161,3 +162,197 @@ func TestLinearModel_InternalCoefficients_AreInExpectedOrder(t *testing.T) {
 	assert.InDelta(t, 3.0, coeffs[2], 1e-9)
 	assert.InDelta(t, 4.0, coeffs[3], 1e-9)
 }
+
+func TestLinearModel_Predict_ErrorBeforeTrain(t *testing.T) {
+	_, _, err := NewModel().Predict(&model.Features{GMV: 1, Users: 1, MarketingCost: 1})
+	require.Error(t, err)
+}
+
+func TestLinearModel_ConcurrentTrainAndPredict(t *testing.T) {
+	base := time.Date(2025, 1, 1, 0, 0, 0, 0, time.UTC)
+	rows := []metrics.Daily{
+		makeDay(base, 100, 10, 5, 80, 40),
+		makeDay(base.AddDate(0, 0, 1), 150, 12, 6, 107, 50),
+		makeDay(base.AddDate(0, 0, 2), 200, 20, 8, 169, 90),
+		makeDay(base.AddDate(0, 0, 3), 250, 25, 9, 207, 105),
+		makeDay(base.AddDate(0, 0, 4), 300, 30, 10, 250, 130),
+	}
+
+	m := NewModel()
+	require.NoError(t, m.Train(rows))
+
+	// Retrain while predicting; run with -race to catch unsynchronized swaps.
+	var wg sync.WaitGroup
+	for g := 0; g < 4; g++ {
+		wg.Add(2)
+		go func() {
+			defer wg.Done()
+			for i := 0; i < 50; i++ {
+				assert.NoError(t, m.Train(rows))
+			}
+		}()
+		go func() {
+			defer wg.Done()
+			for i := 0; i < 50; i++ {
+				_, _, err := m.Predict(&model.Features{GMV: 200, Users: 20, MarketingCost: 8})
+				assert.NoError(t, err)
+			}
+		}()
+	}
+	wg.Wait()
+}
+
+func TestLinearModel_Describe(t *testing.T) {
+	base := time.Date(2025, 3, 1, 0, 0, 0, 0, time.UTC)
+	// FE = 1 + 2*GMV + 3*Users + 4*MC, BE = 2*FE
+	fe := func(gmv, users, mc int) int { return 1 + 2*gmv + 3*users + 4*mc }
+	rows := []metrics.Daily{
+		makeDay(base, 1, 1, 1, fe(1, 1, 1), 2*fe(1, 1, 1)),
+		makeDay(base.AddDate(0, 0, 1), 2, 3, 4, fe(2, 3, 4), 2*fe(2, 3, 4)),
+		makeDay(base.AddDate(0, 0, 2), 5, 6, 7, fe(5, 6, 7), 2*fe(5, 6, 7)),
+		makeDay(base.AddDate(0, 0, 3), 8, 2, 9, fe(8, 2, 9), 2*fe(8, 2, 9)),
+		makeDay(base.AddDate(0, 0, 4), 3, 7, 1, fe(3, 7, 1), 2*fe(3, 7, 1)),
+	}
+
+	m := NewModel()
+	_, err := m.(model.Describer).Describe()
+	require.Error(t, err, "describe before train must fail")
+
+	require.NoError(t, m.Train(rows))
+	d, err := m.(model.Describer).Describe()
+	require.NoError(t, err)
+
+	assert.Equal(t, "linreg", d.Type)
+	assert.Equal(t, 5, d.TrainingRows)
+	assert.False(t, d.TrainedAt.IsZero())
+	assert.Equal(t, []string{"GMV", "Users", "MarketingCost"}, d.FE.Features)
+	assert.NotEmpty(t, d.FE.Formula)
+	assert.InDelta(t, 1.0, d.FE.Intercept, 1e-6)
+	assert.InDelta(t, 2.0, d.FE.Coefficients["GMV"], 1e-6)
+	assert.InDelta(t, 3.0, d.FE.Coefficients["Users"], 1e-6)
+	assert.InDelta(t, 4.0, d.FE.Coefficients["MarketingCost"], 1e-6)
+	assert.InDelta(t, 1.0, d.FE.R2, 1e-6)
+	assert.InDelta(t, 8.0, d.BE.Coefficients["MarketingCost"], 1e-6)
+
+	require.NotNil(t, d.FeatureStats)
+	assert.Equal(t, 1.0, d.FeatureStats.GMV.Min)
+	assert.Equal(t, 8.0, d.FeatureStats.GMV.Max)
+	assert.InDelta(t, 3.8, d.FeatureStats.GMV.Mean, 1e-12)
+
+	p, err := m.(model.Profiler).Profile()
+	require.NoError(t, err)
+	assert.Equal(t, *d.FeatureStats, p)
+}
+
+func TestLinearModel_Explain_ContributionsSumToRaw(t *testing.T) {
+	base := time.Date(2025, 1, 1, 0, 0, 0, 0, time.UTC)
+	rows := []metrics.Daily{
+		makeDay(base, 100, 10, 5, 80, 40),
+		makeDay(base.AddDate(0, 0, 1), 150, 12, 6, 107, 50),
+		makeDay(base.AddDate(0, 0, 2), 200, 20, 8, 169, 90),
+		makeDay(base.AddDate(0, 0, 3), 250, 25, 9, 207, 105),
+		makeDay(base.AddDate(0, 0, 4), 300, 30, 10, 250, 130),
+	}
+	m := NewModel()
+	require.NoError(t, m.Train(rows))
+
+	f := &model.Features{GMV: 180, Users: 18, MarketingCost: 7}
+	ex, err := m.(model.Explainer).Explain(f)
+	require.NoError(t, err)
+
+	fe, be, err := m.Predict(f)
+	require.NoError(t, err)
+	assert.Equal(t, int(fe), ex.FE.Pods)
+	assert.Equal(t, int(be), ex.BE.Pods)
+
+	for _, tier := range []model.TierExplanation{ex.FE, ex.BE} {
+		sum := tier.Intercept
+		for _, c := range tier.Contributions {
+			sum += c
+		}
+		assert.InDelta(t, tier.Raw, sum, 1e-6)
+		assert.Len(t, tier.Contributions, 3)
+		assert.False(t, tier.ClampedToMin)
+		assert.False(t, tier.NaNFallback)
+	}
+}
+
+func TestLinearModel_Explain_ReportsClamp(t *testing.T) {
+	rows := []metrics.Daily{
+		makeDay(time.Now().AddDate(0, 0, 0), 0, 0, 0, 5, 4),
+		makeDay(time.Now().AddDate(0, 0, 1), 2, 5, 1, 4, 3),
+		makeDay(time.Now().AddDate(0, 0, 2), 5, 10, 2, 3, 2),
+		makeDay(time.Now().AddDate(0, 0, 3), 8, 15, 3, 1, 1),
+		makeDay(time.Now().AddDate(0, 0, 4), 12, 18, 5, 1, 1),
+	}
+	m := NewModel()
+	require.NoError(t, m.Train(rows))
+
+	ex, err := m.(model.Explainer).Explain(&model.Features{GMV: 20, Users: 40, MarketingCost: 5})
+	require.NoError(t, err)
+	assert.True(t, ex.FE.ClampedToMin)
+	assert.Less(t, ex.FE.Raw, 0.5)
+	assert.Equal(t, 1, ex.FE.Pods)
+}
+
+func TestLinearModel_PredictInterval(t *testing.T) {
+	base := time.Date(2025, 1, 1, 0, 0, 0, 0, time.UTC)
+	// Noisy linear data so the residual variance is non-zero.
+	rows := []metrics.Daily{
+		makeDay(base, 100, 10, 5, 81, 41),
+		makeDay(base.AddDate(0, 0, 1), 150, 12, 6, 105, 52),
+		makeDay(base.AddDate(0, 0, 2), 200, 20, 8, 170, 88),
+		makeDay(base.AddDate(0, 0, 3), 250, 25, 9, 205, 107),
+		makeDay(base.AddDate(0, 0, 4), 300, 30, 10, 252, 128),
+		makeDay(base.AddDate(0, 0, 5), 220, 18, 12, 180, 99),
+		makeDay(base.AddDate(0, 0, 6), 120, 15, 4, 99, 47),
+	}
+	m := NewModel()
+	require.NoError(t, m.Train(rows))
+	ip := m.(model.IntervalPredictor)
+
+	f := &model.Features{GMV: 180, Users: 18, MarketingCost: 7}
+	i90, err := ip.PredictInterval(f, 0.9)
+	require.NoError(t, err)
+	i99, err := ip.PredictInterval(f, 0.99)
+	require.NoError(t, err)
+
+	fe, be, err := m.Predict(f)
+	require.NoError(t, err)
+	assert.Equal(t, int(fe), i90.FE.Pods(model.BoundPoint))
+	assert.Equal(t, int(be), i90.BE.Pods(model.BoundPoint))
+
+	for _, iv := range []model.Interval{i90, i99} {
+		assert.Less(t, iv.FE.Lower, iv.FE.Point)
+		assert.Greater(t, iv.FE.Upper, iv.FE.Point)
+		assert.InDelta(t, iv.FE.Point-iv.FE.Lower, iv.FE.Upper-iv.FE.Point, 1e-9, "interval is symmetric")
+	}
+	assert.Greater(t, i99.FE.Upper-i99.FE.Lower, i90.FE.Upper-i90.FE.Lower, "higher confidence widens the interval")
+	assert.Greater(t, i99.BE.Upper-i99.BE.Lower, i90.BE.Upper-i90.BE.Lower)
+
+	// Extrapolating far from the training data widens the interval too.
+	far, err := ip.PredictInterval(&model.Features{GMV: 3000, Users: 300, MarketingCost: 100}, 0.9)
+	require.NoError(t, err)
+	assert.Greater(t, far.FE.Upper-far.FE.Lower, i90.FE.Upper-i90.FE.Lower)
+}
+
+func TestLinearModel_PredictInterval_NotEnoughRows(t *testing.T) {
+	base := time.Date(2025, 1, 1, 0, 0, 0, 0, time.UTC)
+	rows := []metrics.Daily{
+		makeDay(base, 100, 10, 5, 80, 40),
+		makeDay(base.AddDate(0, 0, 1), 150, 12, 6, 107, 50),
+		makeDay(base.AddDate(0, 0, 2), 200, 20, 8, 169, 90),
+		makeDay(base.AddDate(0, 0, 3), 250, 25, 9, 207, 105),
+	}
+	m := NewModel()
+	require.NoError(t, m.Train(rows))
+
+	_, err := m.(model.IntervalPredictor).PredictInterval(&model.Features{GMV: 1, Users: 1, MarketingCost: 1}, 0.9)
+	assert.Error(t, err, "n == p leaves no residual degrees of freedom")
+}
+
+func TestLinearModel_PredictInterval_InvalidConfidence(t *testing.T) {
+	m := NewModel()
+	_, err := m.(model.IntervalPredictor).PredictInterval(&model.Features{}, 1.5)
+	assert.Error(t, err)
+}
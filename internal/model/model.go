@@ -9,6 +9,12 @@ type Features struct {
 	MarketingCost float64 `json:"marketing_cost"` // Marketing expenditure
 }
 
+// Validate applies the KPI rules of metrics.Daily to f and returns one
+// metrics.FieldError per invalid field, or nil.
+func (f Features) Validate() []metrics.FieldError {
+	return metrics.ValidateKPIs(f.GMV, f.Users, f.MarketingCost)
+}
+
 func FeaturesFromDaily(d metrics.Daily) Features {
 	return Features{
 		GMV:           d.GMV,
@@ -17,6 +23,11 @@ func FeaturesFromDaily(d metrics.Daily) Features {
 	}
 }
 
+// Values returns f in the order of FeatureNames.
+func (f Features) Values() []float64 {
+	return []float64{f.GMV, f.Users, f.MarketingCost}
+}
+
 // BEPods represents the predicted number of Back-End Pods.
 type BEPods int
 
@@ -24,9 +35,17 @@ type BEPods int
 type FEPods int
 
 // Model defines an interface for predicting FEPods and BEPods based on given features.
+// Implementations must be safe for concurrent use: Train may be called to
+// retrain while Predict is serving requests. Train should build the new state
+// off to the side and swap it in atomically, so Predict observes either the
+// previous or the new model, never a mix. A failed Train keeps the previous state.
 type Model interface {
 	// Train trains the model using the provided daily metrics.
 	Train([]metrics.Daily) error
 	// Predict takes Features as input and returns the predicted FEPods, BEPods, and any potential error.
 	Predict(features *Features) (FEPods, BEPods, error)
 }
+
+// Factory returns a new, untrained Model. It is used wherever several
+// independent models must be fitted, such as cross-validation.
+type Factory func() Model
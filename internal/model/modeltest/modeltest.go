@@ -0,0 +1,21 @@
+// Package modeltest provides training data fixtures for model tests.
+package modeltest
+
+import (
+	"time"
+
+	"github.com/thisiscetin/podpredict/internal/metrics"
+)
+
+// Start is the date of the first Day.
+var Start = time.Date(2025, 1, 1, 0, 0, 0, 0, time.UTC)
+
+// Day returns the row of the i-th day after Start with the given KPIs and
+// pods. It panics if they are invalid.
+func Day(i int, gmv float64, users int, mc float64, fe, be int) metrics.Daily {
+	d, err := metrics.NewDaily(Start.AddDate(0, 0, i), gmv, users, mc, &fe, &be)
+	if err != nil {
+		panic(err)
+	}
+	return d
+}
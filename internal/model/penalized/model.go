@@ -0,0 +1,287 @@
+// Package penalized implements linear regression with a penalty on the
+// coefficients, shared by the ridge and lasso models.
+package penalized
+
+import (
+	"fmt"
+	"math"
+	"strings"
+
+	"github.com/thisiscetin/podpredict/internal/metrics"
+	"github.com/thisiscetin/podpredict/internal/model"
+)
+
+// DefaultFolds is the number of cross-validation folds used to select lambda.
+const DefaultFolds = 5
+
+// Solver fits the coefficients b minimizing ||y − Xb||²/(2n) + lambda·P(b)
+// for a penalty P. The columns of x are standardized to mean 0 and
+// variance 1 (or all zero when constant) and y is centered.
+type Solver func(x [][]float64, y []float64, lambda float64) ([]float64, error)
+
+// Options configures a penalized Model.
+type Options struct {
+	// Type names the model in its model.Description, e.g. "ridge".
+	Type string
+	// Solve fits the coefficients for a given lambda.
+	Solve Solver
+	// Lambda is the regularization strength. 0 selects it for each tier by
+	// k-fold cross-validation over a grid scaled to the data.
+	Lambda float64
+	// Folds is the number of cross-validation folds; DefaultFolds if 0.
+	Folds int
+}
+
+// penalizedModel implements model.Model with a penalized linear fit per tier.
+type penalizedModel struct {
+	opts Options
+	model.Latest[fit]
+}
+
+// fit is a pair of trained FE and BE tiers.
+type fit struct {
+	fe, be tier
+}
+
+// tier is a linear predictor on the original feature scale.
+type tier struct {
+	coeffs    []float64
+	intercept float64
+	lambda    float64
+	r2        float64
+}
+
+// NewModel returns a Model that fits FE and BE pods with opts.Solve.
+func NewModel(opts Options) model.Model {
+	if opts.Folds == 0 {
+		opts.Folds = DefaultFolds
+	}
+	return &penalizedModel{opts: opts}
+}
+
+// Train fits the FE and BE tiers on the rows with both pods.
+func (m *penalizedModel) Train(rows []metrics.Daily) error {
+	ts, err := model.NewTrainingSet(rows)
+	if err != nil {
+		return err
+	}
+	fe, err := m.train(ts.X, ts.FE)
+	if err != nil {
+		return fmt.Errorf("fitting FE pods: %w", err)
+	}
+	be, err := m.train(ts.X, ts.BE)
+	if err != nil {
+		return fmt.Errorf("fitting BE pods: %w", err)
+	}
+	m.Store(fit{fe: fe, be: be}, ts)
+	return nil
+}
+
+// train fits one tier, selecting lambda by cross-validation unless fixed.
+func (m *penalizedModel) train(xs [][]float64, y []float64) (tier, error) {
+	lambda := m.opts.Lambda
+	if lambda == 0 {
+		var err error
+		if lambda, err = m.selectLambda(xs, y); err != nil {
+			return tier{}, err
+		}
+	}
+	t, err := fitTier(m.opts.Solve, xs, y, lambda)
+	if err != nil {
+		return tier{}, err
+	}
+	t.r2 = model.R2(t.predict, xs, y)
+	return t, nil
+}
+
+// selectLambda returns the lambda of Grid(xs, y) with the lowest mean
+// squared error across folds; row i is held out in fold i%k. With fewer
+// than two rows there is nothing to validate on and the largest is used.
+func (m *penalizedModel) selectLambda(xs [][]float64, y []float64) (float64, error) {
+	grid := Grid(xs, y)
+	k := min(m.opts.Folds, len(xs))
+	if k < 2 {
+		return grid[0], nil
+	}
+
+	best, bestMSE := grid[0], math.Inf(1)
+	for _, lambda := range grid {
+		var sse float64
+		for fold := 0; fold < k; fold++ {
+			var trainX, testX [][]float64
+			var trainY, testY []float64
+			for i := range xs {
+				if i%k == fold {
+					testX, testY = append(testX, xs[i]), append(testY, y[i])
+				} else {
+					trainX, trainY = append(trainX, xs[i]), append(trainY, y[i])
+				}
+			}
+			t, err := fitTier(m.opts.Solve, trainX, trainY, lambda)
+			if err != nil {
+				return 0, err
+			}
+			for i, x := range testX {
+				d := t.predict(x) - testY[i]
+				sse += d * d
+			}
+		}
+		if mse := sse / float64(len(xs)); mse < bestMSE {
+			best, bestMSE = lambda, mse
+		}
+	}
+	return best, nil
+}
+
+// GridSize is the number of lambdas tried by cross-validation.
+const GridSize = 20
+
+// Grid returns GridSize lambdas spaced geometrically from the smallest
+// lambda that zeroes every lasso coefficient, max_j |x_jᵀy|/n on
+// standardized data, down to 1e-4 times that, largest first.
+func Grid(xs [][]float64, y []float64) []float64 {
+	x, yc, _, _, _ := standardize(xs, y)
+	var top float64
+	for j := range model.FeatureNames {
+		var dot float64
+		for i := range x {
+			dot += x[i][j] * yc[i]
+		}
+		top = math.Max(top, math.Abs(dot)/float64(len(x)))
+	}
+	if top == 0 {
+		top = 1
+	}
+	grid := make([]float64, GridSize)
+	for i := range grid {
+		grid[i] = top * math.Pow(1e-4, float64(i)/float64(GridSize-1))
+	}
+	return grid
+}
+
+// fitTier standardizes xs, solves for lambda and maps the coefficients
+// back to the original scale.
+func fitTier(solve Solver, xs [][]float64, y []float64, lambda float64) (tier, error) {
+	x, yc, means, sds, yMean := standardize(xs, y)
+	b, err := solve(x, yc, lambda)
+	if err != nil {
+		return tier{}, err
+	}
+	t := tier{coeffs: make([]float64, len(model.FeatureNames)), intercept: yMean, lambda: lambda}
+	for j := range model.FeatureNames {
+		if sds[j] == 0 {
+			continue
+		}
+		t.coeffs[j] = b[j] / sds[j]
+		t.intercept -= t.coeffs[j] * means[j]
+	}
+	return t, nil
+}
+
+// standardize scales each column of xs to mean 0 and variance 1, leaving
+// constant columns at 0, and centers y.
+func standardize(xs [][]float64, y []float64) (x [][]float64, yc, means, sds []float64, yMean float64) {
+	n, p := float64(len(xs)), len(model.FeatureNames)
+	means, sds = make([]float64, p), make([]float64, p)
+	for i, row := range xs {
+		for j, v := range row {
+			means[j] += v / n
+		}
+		yMean += y[i] / n
+	}
+	for _, row := range xs {
+		for j, v := range row {
+			sds[j] += (v - means[j]) * (v - means[j]) / n
+		}
+	}
+	for j := range sds {
+		sds[j] = math.Sqrt(sds[j])
+	}
+
+	x, yc = make([][]float64, len(xs)), make([]float64, len(y))
+	for i, row := range xs {
+		x[i] = make([]float64, p)
+		for j, v := range row {
+			if sds[j] > 0 {
+				x[i][j] = (v - means[j]) / sds[j]
+			}
+		}
+		yc[i] = y[i] - yMean
+	}
+	return x, yc, means, sds, yMean
+}
+
+func (t tier) predict(x []float64) float64 {
+	v := t.intercept
+	for j, c := range t.coeffs {
+		v += c * x[j]
+	}
+	return v
+}
+
+// Predict evaluates the linear fit of each tier and rounds to at least 1
+// pod.
+func (m *penalizedModel) Predict(f *model.Features) (model.FEPods, model.BEPods, error) {
+	cur, err := m.Load()
+	if err != nil {
+		return 0, 0, err
+	}
+	in := f.Values()
+	fe, _, _ := model.ToPods(cur.Fit.fe.predict(in))
+	be, _, _ := model.ToPods(cur.Fit.be.predict(in))
+	return model.FEPods(fe), model.BEPods(be), nil
+}
+
+// Explain predicts like Predict and breaks each tier down into the
+// intercept and per-feature contributions (coefficient × value).
+func (m *penalizedModel) Explain(f *model.Features) (model.Explanation, error) {
+	cur, err := m.Load()
+	if err != nil {
+		return model.Explanation{}, err
+	}
+	in := f.Values()
+	return model.Explanation{FE: cur.Fit.fe.explain(in), BE: cur.Fit.be.explain(in)}, nil
+}
+
+func (t tier) explain(in []float64) model.TierExplanation {
+	out := model.TierExplanation{
+		Intercept:     t.intercept,
+		Contributions: make(map[string]float64, len(in)),
+	}
+	raw := t.predict(in)
+	if !math.IsNaN(raw) && !math.IsInf(raw, 0) {
+		out.Raw = raw
+	}
+	for j, name := range model.FeatureNames {
+		out.Contributions[name] = t.coeffs[j] * in[j]
+	}
+	out.Pods, out.NaNFallback, out.ClampedToMin = model.ToPods(raw)
+	return out
+}
+
+// Describe reports the coefficients, lambda and fit of the current tiers.
+func (m *penalizedModel) Describe() (model.Description, error) {
+	cur, err := m.Load()
+	if err != nil {
+		return model.Description{}, err
+	}
+	return cur.Describe(m.opts.Type, cur.Fit.fe.describe(), cur.Fit.be.describe()), nil
+}
+
+func (t tier) describe() model.Regressor {
+	out := model.Regressor{
+		Features:     model.FeatureNames,
+		Coefficients: make(map[string]float64, len(model.FeatureNames)),
+		Intercept:    t.intercept,
+		R2:           t.r2,
+		Lambda:       t.lambda,
+	}
+	var b strings.Builder
+	fmt.Fprintf(&b, "Predicted = %.4f", t.intercept)
+	for j, name := range model.FeatureNames {
+		out.Coefficients[name] = t.coeffs[j]
+		fmt.Fprintf(&b, " + %s*%.4f", name, t.coeffs[j])
+	}
+	out.Formula = b.String()
+	return out
+}
@@ -0,0 +1,82 @@
+package penalized
+
+import (
+	"math"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"github.com/thisiscetin/podpredict/internal/metrics"
+	"github.com/thisiscetin/podpredict/internal/model"
+	"github.com/thisiscetin/podpredict/internal/model/modeltest"
+)
+
+// zero is a Solver that shrinks every coefficient to zero.
+func zero(x [][]float64, _ []float64, _ float64) ([]float64, error) {
+	return make([]float64, len(x[0])), nil
+}
+
+func TestModel_ZeroCoefficientsPredictTheMean(t *testing.T) {
+	m := NewModel(Options{Type: "zero", Solve: zero, Lambda: 1})
+	_, _, err := m.Predict(&model.Features{})
+	require.Error(t, err, "predict before train must fail")
+	require.Error(t, m.Train(nil))
+
+	require.NoError(t, m.Train([]metrics.Daily{
+		modeltest.Day(0, 10, 1, 1, 2, 10),
+		modeltest.Day(1, 20, 2, 1, 4, 20),
+		modeltest.Day(2, 30, 3, 1, 6, 30),
+	}))
+	fe, be, err := m.Predict(&model.Features{GMV: 1000})
+	require.NoError(t, err)
+	assert.Equal(t, model.FEPods(4), fe)
+	assert.Equal(t, model.BEPods(20), be)
+
+	d, err := m.(model.Describer).Describe()
+	require.NoError(t, err)
+	assert.Equal(t, "zero", d.Type)
+	assert.Equal(t, 3, d.TrainingRows)
+	assert.Equal(t, 1.0, d.FE.Lambda)
+	assert.Equal(t, 4.0, d.FE.Intercept)
+	assert.Equal(t, 0.0, d.FE.R2)
+	require.NotNil(t, d.FeatureStats)
+	assert.Equal(t, 30.0, d.FeatureStats.GMV.Max)
+}
+
+func TestFitTier_MapsCoefficientsToOriginalScale(t *testing.T) {
+	// y = 3 + 2·x0 - x1; x2 is constant and must get a zero coefficient.
+	xs := [][]float64{{1, 5, 7}, {2, 3, 7}, {4, 4, 7}, {7, 1, 7}}
+	var y []float64
+	for _, x := range xs {
+		y = append(y, 3+2*x[0]-x[1])
+	}
+	// Solving with the standardized least-squares coefficients must
+	// reproduce the original ones.
+	exact := func(x [][]float64, yc []float64, _ float64) ([]float64, error) {
+		_, _, _, sds, _ := standardize(xs, y)
+		return []float64{2 * sds[0], -1 * sds[1], 0}, nil
+	}
+	tr, err := fitTier(exact, xs, y, 0.5)
+	require.NoError(t, err)
+	assert.InDelta(t, 2, tr.coeffs[0], 1e-12)
+	assert.InDelta(t, -1, tr.coeffs[1], 1e-12)
+	assert.Equal(t, 0.0, tr.coeffs[2])
+	assert.InDelta(t, 3, tr.intercept, 1e-12)
+	assert.InDelta(t, 1, model.R2(tr.predict, xs, y), 1e-12)
+}
+
+func TestGrid(t *testing.T) {
+	xs := [][]float64{{1, 0, 0}, {2, 0, 0}, {3, 0, 0}}
+	g := Grid(xs, []float64{1, 2, 3})
+	require.Len(t, g, GridSize)
+	// max |x_jᵀy|/n on standardized data is the std of y, sqrt(2/3).
+	assert.InDelta(t, math.Sqrt(2.0/3), g[0], 1e-12)
+	assert.InDelta(t, g[0]*1e-4, g[GridSize-1], 1e-12)
+	for i := 1; i < len(g); i++ {
+		assert.Less(t, g[i], g[i-1])
+	}
+
+	// A constant target still gets a usable grid.
+	assert.Equal(t, 1.0, Grid(xs, []float64{5, 5, 5})[0])
+}
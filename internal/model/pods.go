@@ -0,0 +1,27 @@
+package model
+
+import "math"
+
+// ToPods converts a raw model output into a pod count of at least 1,
+// reporting whether the NaN/Inf fallback or the minimum clamp was applied.
+func ToPods(v float64) (pods int, nanFallback, clamped bool) {
+	nanFallback = math.IsNaN(v) || math.IsInf(v, 0)
+	rounded := safeRound(v)
+	pods = clampMinInt(rounded, 1)
+	return pods, nanFallback, !nanFallback && pods != rounded
+}
+
+// safeRound handles NaN/Inf defensively before rounding.
+func safeRound(v float64) int {
+	if math.IsNaN(v) || math.IsInf(v, 0) {
+		return 1 // fallback to minimum if solver returns invalid
+	}
+	return int(math.Round(v))
+}
+
+func clampMinInt(v, min int) int {
+	if v < min {
+		return min
+	}
+	return v
+}
@@ -0,0 +1,25 @@
+package model
+
+import (
+	"math"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestToPods(t *testing.T) {
+	pods, nan, clamped := ToPods(math.NaN())
+	assert.Equal(t, 1, pods)
+	assert.True(t, nan)
+	assert.False(t, clamped)
+
+	pods, nan, clamped = ToPods(-3.2)
+	assert.Equal(t, 1, pods)
+	assert.False(t, nan)
+	assert.True(t, clamped)
+
+	pods, nan, clamped = ToPods(4.6)
+	assert.Equal(t, 5, pods)
+	assert.False(t, nan)
+	assert.False(t, clamped)
+}
@@ -0,0 +1,103 @@
+package model
+
+import (
+	"fmt"
+	"math"
+
+	"github.com/thisiscetin/podpredict/internal/metrics"
+)
+
+// Profiler is implemented by models that record the distribution of the
+// features they were trained on. It is optional; callers should
+// type-assert a Model to discover support.
+type Profiler interface {
+	// Profile returns the feature statistics of the current training set,
+	// or an error if the model has not been trained yet.
+	Profile() (Profile, error)
+}
+
+// FeatureStats summarizes one feature over the training rows.
+type FeatureStats struct {
+	Min  float64 `json:"min"`
+	Max  float64 `json:"max"`
+	Mean float64 `json:"mean"`
+	// Std is the population standard deviation.
+	Std float64 `json:"std"`
+}
+
+// Profile holds FeatureStats for each input feature.
+type Profile struct {
+	GMV           FeatureStats `json:"gmv"`
+	Users         FeatureStats `json:"users"`
+	MarketingCost FeatureStats `json:"marketing_cost"`
+}
+
+// NewProfile computes the Profile of the rows a model trains on: those
+// with both FE and BE pods. It is the zero Profile when there are none.
+func NewProfile(rows []metrics.Daily) Profile {
+	var gmv, users, mc []float64
+	for _, d := range rows {
+		if !d.HasPods() {
+			continue
+		}
+		gmv = append(gmv, d.GMV)
+		users = append(users, d.Users)
+		mc = append(mc, d.MarketingCost)
+	}
+	return Profile{
+		GMV:           featureStats(gmv),
+		Users:         featureStats(users),
+		MarketingCost: featureStats(mc),
+	}
+}
+
+func featureStats(vs []float64) FeatureStats {
+	if len(vs) == 0 {
+		return FeatureStats{}
+	}
+	s := FeatureStats{Min: vs[0], Max: vs[0]}
+	for _, v := range vs {
+		s.Min = math.Min(s.Min, v)
+		s.Max = math.Max(s.Max, v)
+		s.Mean += v
+	}
+	s.Mean /= float64(len(vs))
+	for _, v := range vs {
+		s.Std += (v - s.Mean) * (v - s.Mean)
+	}
+	s.Std = math.Sqrt(s.Std / float64(len(vs)))
+	return s
+}
+
+// RangeViolation reports a feature value outside the training range.
+type RangeViolation struct {
+	// Feature is the JSON name of the feature, as in Features.
+	Feature string  `json:"feature"`
+	Value   float64 `json:"value"`
+	Min     float64 `json:"min"`
+	Max     float64 `json:"max"`
+}
+
+func (v RangeViolation) String() string {
+	return fmt.Sprintf("%s %g is outside the training range [%g, %g]", v.Feature, v.Value, v.Min, v.Max)
+}
+
+// OutOfRange returns a RangeViolation for each feature of f outside the
+// [Min, Max] envelope of p, in field order, or nil.
+func (p Profile) OutOfRange(f *Features) []RangeViolation {
+	var out []RangeViolation
+	for _, c := range []struct {
+		name  string
+		v     float64
+		stats FeatureStats
+	}{
+		{"gmv", f.GMV, p.GMV},
+		{"users", f.Users, p.Users},
+		{"marketing_cost", f.MarketingCost, p.MarketingCost},
+	} {
+		if c.v < c.stats.Min || c.v > c.stats.Max {
+			out = append(out, RangeViolation{Feature: c.name, Value: c.v, Min: c.stats.Min, Max: c.stats.Max})
+		}
+	}
+	return out
+}
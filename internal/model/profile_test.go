@@ -0,0 +1,42 @@
+package model
+
+import (
+	"math"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"github.com/thisiscetin/podpredict/internal/metrics"
+)
+
+func TestNewProfile(t *testing.T) {
+	pods := 1
+	day := time.Date(2025, 1, 1, 0, 0, 0, 0, time.UTC)
+	var rows []metrics.Daily
+	for i, gmv := range []float64{2, 4, 4, 4, 5, 5, 7, 9} {
+		d, err := metrics.NewDaily(day.AddDate(0, 0, i), gmv, 10, float64(i), &pods, &pods)
+		require.NoError(t, err)
+		rows = append(rows, d)
+	}
+	// Rows without pods are not trained on, so they are not profiled.
+	d, err := metrics.NewDaily(day.AddDate(0, 0, 8), 1000, 10, 0, nil, nil)
+	require.NoError(t, err)
+	rows = append(rows, d)
+
+	p := NewProfile(rows)
+	assert.Equal(t, FeatureStats{Min: 2, Max: 9, Mean: 5, Std: 2}, p.GMV)
+	assert.Equal(t, FeatureStats{Min: 10, Max: 10, Mean: 10, Std: 0}, p.Users)
+	assert.Equal(t, 0.0, p.MarketingCost.Min)
+	assert.Equal(t, 7.0, p.MarketingCost.Max)
+	assert.InDelta(t, math.Sqrt(5.25), p.MarketingCost.Std, 1e-12)
+
+	assert.Nil(t, p.OutOfRange(&Features{GMV: 2, Users: 10, MarketingCost: 7}))
+	got := p.OutOfRange(&Features{GMV: 90, Users: 10, MarketingCost: -1})
+	assert.Equal(t, []RangeViolation{
+		{Feature: "gmv", Value: 90, Min: 2, Max: 9},
+		{Feature: "marketing_cost", Value: -1, Min: 0, Max: 7},
+	}, got)
+	assert.Equal(t, "gmv 90 is outside the training range [2, 9]", got[0].String())
+}
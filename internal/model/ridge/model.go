@@ -0,0 +1,69 @@
+// Package ridge implements ridge regression: least squares with an L2
+// penalty, which keeps the coefficients of collinear KPIs such as GMV and
+// Users small and stable between retrains.
+package ridge
+
+import (
+	"errors"
+
+	"gonum.org/v1/gonum/mat"
+
+	"github.com/thisiscetin/podpredict/internal/model"
+	"github.com/thisiscetin/podpredict/internal/model/penalized"
+)
+
+// Options configures a ridge Model.
+type Options struct {
+	// Lambda is the L2 regularization strength on standardized features.
+	// 0 selects it by cross-validation.
+	Lambda float64
+	// Folds is the number of cross-validation folds; penalized.DefaultFolds if 0.
+	Folds int
+}
+
+// NewModel returns a ridge regression Model.
+func NewModel(opts Options) model.Model {
+	return penalized.NewModel(penalized.Options{
+		Type:   "ridge",
+		Solve:  Solve,
+		Lambda: opts.Lambda,
+		Folds:  opts.Folds,
+	})
+}
+
+// Solve minimizes ||y − Xb||²/(2n) + lambda·||b||²/2 in closed form,
+// b = (XᵀX/n + lambda·I)⁻¹ Xᵀy/n. It is a penalized.Solver.
+func Solve(x [][]float64, y []float64, lambda float64) ([]float64, error) {
+	n := float64(len(x))
+	p := len(x[0])
+
+	a := mat.NewSymDense(p, nil)
+	rhs := mat.NewVecDense(p, nil)
+	for j := 0; j < p; j++ {
+		for k := j; k < p; k++ {
+			var dot float64
+			for i := range x {
+				dot += x[i][j] * x[i][k]
+			}
+			if j == k {
+				dot += n * lambda
+			}
+			a.SetSym(j, k, dot/n)
+		}
+		var dot float64
+		for i := range x {
+			dot += x[i][j] * y[i]
+		}
+		rhs.SetVec(j, dot/n)
+	}
+
+	var chol mat.Cholesky
+	if !chol.Factorize(a) {
+		return nil, errors.New("ridge system is not positive definite; lambda must be positive")
+	}
+	var b mat.VecDense
+	if err := chol.SolveVecTo(&b, rhs); err != nil {
+		return nil, err
+	}
+	return b.RawVector().Data, nil
+}
@@ -0,0 +1,79 @@
+package ridge
+
+import (
+	"math/rand"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"github.com/thisiscetin/podpredict/internal/metrics"
+	"github.com/thisiscetin/podpredict/internal/model"
+	"github.com/thisiscetin/podpredict/internal/model/modeltest"
+)
+
+func TestRidge_SmallLambdaRecoversLinearFit(t *testing.T) {
+	// FE = 1 + 2*GMV + 3*Users + 4*MC, BE = 2*FE
+	fe := func(gmv, users, mc int) int { return 1 + 2*gmv + 3*users + 4*mc }
+	var rows []metrics.Daily
+	for i, x := range [][3]int{{1, 1, 1}, {2, 3, 4}, {5, 6, 7}, {8, 2, 9}, {3, 7, 1}, {6, 4, 2}} {
+		rows = append(rows, modeltest.Day(i, float64(x[0]), x[1], float64(x[2]), fe(x[0], x[1], x[2]), 2*fe(x[0], x[1], x[2])))
+	}
+
+	m := NewModel(Options{Lambda: 1e-9})
+	require.NoError(t, m.Train(rows))
+	d, err := m.(model.Describer).Describe()
+	require.NoError(t, err)
+	assert.Equal(t, "ridge", d.Type)
+	assert.InDelta(t, 2.0, d.FE.Coefficients["GMV"], 1e-6)
+	assert.InDelta(t, 3.0, d.FE.Coefficients["Users"], 1e-6)
+	assert.InDelta(t, 4.0, d.FE.Coefficients["MarketingCost"], 1e-6)
+	assert.InDelta(t, 1.0, d.FE.Intercept, 1e-6)
+	assert.InDelta(t, 8.0, d.BE.Coefficients["MarketingCost"], 1e-6)
+
+	gotFE, gotBE, err := m.Predict(&model.Features{GMV: 10, Users: 10, MarketingCost: 10})
+	require.NoError(t, err)
+	assert.Equal(t, model.FEPods(91), gotFE)
+	assert.Equal(t, model.BEPods(182), gotBE)
+
+	ex, err := m.(model.Explainer).Explain(&model.Features{GMV: 10, Users: 10, MarketingCost: 10})
+	require.NoError(t, err)
+	sum := ex.FE.Intercept
+	for _, c := range ex.FE.Contributions {
+		sum += c
+	}
+	assert.InDelta(t, ex.FE.Raw, sum, 1e-9)
+	assert.Equal(t, 91, ex.FE.Pods)
+}
+
+func TestRidge_CollinearFeaturesShareTheWeight(t *testing.T) {
+	// Users tracks GMV almost exactly; pods depend on their sum.
+	r := rand.New(rand.NewSource(1))
+	var rows []metrics.Daily
+	for i := 0; i < 60; i++ {
+		gmv := 100 + r.Float64()*100
+		users := int(gmv + r.NormFloat64())
+		pods := int(0.1*gmv + 0.1*float64(users) + r.NormFloat64())
+		rows = append(rows, modeltest.Day(i, gmv, users, 10, pods, pods))
+	}
+
+	m := NewModel(Options{})
+	require.NoError(t, m.Train(rows))
+	d, err := m.(model.Describer).Describe()
+	require.NoError(t, err)
+
+	assert.Positive(t, d.FE.Lambda, "lambda is selected by cross-validation")
+	assert.Positive(t, d.FE.Coefficients["GMV"])
+	assert.Positive(t, d.FE.Coefficients["Users"])
+	assert.InDelta(t, 0.2, d.FE.Coefficients["GMV"]+d.FE.Coefficients["Users"], 0.05)
+}
+
+func TestSolve_RequiresPositiveLambdaForSingularData(t *testing.T) {
+	x := [][]float64{{1, 1}, {-1, -1}}
+	_, err := Solve(x, []float64{1, -1}, 0)
+	assert.Error(t, err)
+
+	b, err := Solve(x, []float64{1, -1}, 0.5)
+	require.NoError(t, err)
+	assert.InDelta(t, b[0], b[1], 1e-12, "identical columns get identical weights")
+}
@@ -0,0 +1,323 @@
+// Package seasonal implements additive Holt-Winters exponential smoothing
+// of the daily pod series: a level, a trend and a weekly season, plus an
+// optional holiday effect. It forecasts pods from the date alone.
+package seasonal
+
+import (
+	"errors"
+	"math"
+	"sort"
+	"sync/atomic"
+	"time"
+
+	"github.com/thisiscetin/podpredict/internal/metrics"
+	"github.com/thisiscetin/podpredict/internal/model"
+)
+
+// Options configures a seasonal Model.
+// Zero values fall back to the defaults documented on each field.
+type Options struct {
+	// Period is the season length in days, default 7.
+	Period int
+	// Alpha, Beta and Gamma smooth the level, trend and season. Each must
+	// be in (0, 1); 0 selects them by grid search on the one-step-ahead
+	// squared error of the training series.
+	Alpha, Beta, Gamma float64
+	// Holidays lists days whose pods deviate from the season. They do not
+	// update the level, trend or season; their mean deviation is learned
+	// and added when forecasting a holiday.
+	Holidays []time.Time
+}
+
+func (o Options) withDefaults() Options {
+	if o.Period <= 0 {
+		o.Period = 7
+	}
+	return o
+}
+
+// Parameter grids searched when a smoothing factor is not set.
+var (
+	alphas = []float64{0.1, 0.2, 0.3, 0.4, 0.5, 0.6, 0.7, 0.8, 0.9}
+	betas  = []float64{0.01, 0.05, 0.1, 0.2, 0.3}
+	gammas = []float64{0.05, 0.1, 0.2, 0.3, 0.5}
+)
+
+// seasonalModel implements model.Model and model.Forecaster.
+type seasonalModel struct {
+	opts     Options
+	holidays map[time.Time]bool
+	// cur holds the fit of the last successful Train(), nil until then.
+	cur atomic.Pointer[fit]
+}
+
+// fit is an immutable pair of smoothed FE and BE series.
+type fit struct {
+	fe, be    smoother
+	start     time.Time // day of the first training row
+	last      time.Time // day of the last training row
+	rows      int
+	trainedAt time.Time
+}
+
+// smoother is the state of one series after its last observation.
+type smoother struct {
+	alpha, beta, gamma float64
+	level, trend       float64
+	season             []float64 // indexed by day offset from start mod period
+	holiday            float64   // mean deviation on holidays
+	r2                 float64   // of the one-step-ahead forecasts
+	// first is the offset of the first day after initialization.
+	first int
+	// fitted holds the one-step-ahead forecast of every training day from
+	// first on; earlier entries are unset.
+	fitted []float64
+}
+
+// NewModel returns a Holt-Winters Model.
+func NewModel(opts Options) model.Model {
+	m := &seasonalModel{opts: opts.withDefaults(), holidays: map[time.Time]bool{}}
+	for _, d := range opts.Holidays {
+		m.holidays[day(d)] = true
+	}
+	return m
+}
+
+// day truncates t to its UTC date.
+func day(t time.Time) time.Time {
+	return t.UTC().Truncate(24 * time.Hour)
+}
+
+// Train smooths the daily FE and BE pods of the rows with both pods, in
+// date order. Rows on the same day are averaged and missing days are
+// interpolated linearly.
+func (m *seasonalModel) Train(rows []metrics.Daily) error {
+	byDay := map[time.Time][3]float64{} // fe sum, be sum, count
+	for _, d := range rows {
+		if !d.HasPods() {
+			continue
+		}
+		fePods, bePods, _ := d.Pods()
+		k := day(d.Date)
+		v := byDay[k]
+		byDay[k] = [3]float64{v[0] + float64(fePods), v[1] + float64(bePods), v[2] + 1}
+	}
+	if len(byDay) == 0 {
+		return errors.New("no valid rows with FE/BE pods")
+	}
+
+	days := make([]time.Time, 0, len(byDay))
+	for k := range byDay {
+		days = append(days, k)
+	}
+	sort.Slice(days, func(i, j int) bool { return days[i].Before(days[j]) })
+	start, last := days[0], days[len(days)-1]
+
+	n := int(last.Sub(start).Hours()/24) + 1
+	fe, be := make([]float64, n), make([]float64, n)
+	known := make([]bool, n)
+	for k, v := range byDay {
+		t := int(k.Sub(start).Hours() / 24)
+		fe[t], be[t], known[t] = v[0]/v[2], v[1]/v[2], true
+	}
+	interpolate(fe, known)
+	interpolate(be, known)
+
+	holiday := make([]bool, n)
+	for t := range holiday {
+		holiday[t] = m.holidays[start.AddDate(0, 0, t)]
+	}
+	m.cur.Store(&fit{
+		fe:        m.smooth(fe, holiday),
+		be:        m.smooth(be, holiday),
+		start:     start,
+		last:      last,
+		rows:      len(byDay),
+		trainedAt: time.Now().UTC(),
+	})
+	return nil
+}
+
+// interpolate fills the unknown values of ys linearly between known ones.
+// Known values exist at both ends.
+func interpolate(ys []float64, known []bool) {
+	prev := 0
+	for t := 1; t < len(ys); t++ {
+		if !known[t] {
+			continue
+		}
+		for g := prev + 1; g < t; g++ {
+			ys[g] = ys[prev] + (ys[t]-ys[prev])*float64(g-prev)/float64(t-prev)
+		}
+		prev = t
+	}
+}
+
+// smooth fits ys with the configured or best-scoring smoothing factors.
+func (m *seasonalModel) smooth(ys []float64, holiday []bool) smoother {
+	grid := func(fixed float64, candidates []float64) []float64 {
+		if fixed > 0 {
+			return []float64{fixed}
+		}
+		return candidates
+	}
+	var best smoother
+	bestSSE := math.Inf(1)
+	for _, a := range grid(m.opts.Alpha, alphas) {
+		for _, b := range grid(m.opts.Beta, betas) {
+			for _, g := range grid(m.opts.Gamma, gammas) {
+				s, sse := run(ys, holiday, m.opts.Period, a, b, g)
+				if sse < bestSSE {
+					best, bestSSE = s, sse
+				}
+			}
+		}
+	}
+	return best
+}
+
+// run applies additive Holt-Winters to ys and returns the final state and
+// the squared error of its one-step-ahead forecasts on non-holidays.
+//
+// With two full periods, the level and season start from the first
+// period and the trend from the change between the first two; otherwise
+// the season stays zero (Holt's linear method). Holidays are forecast but
+// do not update the state; their mean residual becomes the holiday effect.
+func run(ys []float64, holiday []bool, period int, alpha, beta, gamma float64) (smoother, float64) {
+	n := len(ys)
+	s := smoother{alpha: alpha, beta: beta, gamma: gamma, season: make([]float64, period), fitted: make([]float64, n)}
+
+	var first int
+	switch {
+	case n >= 2*period:
+		var m1, m2 float64
+		for t := 0; t < period; t++ {
+			m1 += ys[t] / float64(period)
+			m2 += ys[period+t] / float64(period)
+		}
+		s.trend = (m2 - m1) / float64(period)
+		// The mean of the first period sits at its center.
+		center := float64(period-1) / 2
+		s.level = m1 + s.trend*center
+		for t := 0; t < period; t++ {
+			s.season[t] = ys[t] - (m1 + s.trend*(float64(t)-center))
+		}
+		first = period
+	case n >= 2:
+		s.level, s.trend, first = ys[1], ys[1]-ys[0], 2
+	default:
+		s.level, first = ys[0], 1
+	}
+
+	s.first = first
+	var sse, ssTot, mean, holidaySum float64
+	var scored, holidays int
+	for t := first; t < n; t++ {
+		mean += ys[t]
+	}
+	if n > first {
+		mean /= float64(n - first)
+	}
+	for t := first; t < n; t++ {
+		i := t % period
+		forecast := s.level + s.trend + s.season[i]
+		s.fitted[t] = forecast
+		if holiday[t] {
+			holidaySum += ys[t] - forecast
+			holidays++
+			s.level += s.trend
+			continue
+		}
+		d := ys[t] - forecast
+		sse += d * d
+		ssTot += (ys[t] - mean) * (ys[t] - mean)
+		scored++
+
+		level := alpha*(ys[t]-s.season[i]) + (1-alpha)*(s.level+s.trend)
+		s.trend = beta*(level-s.level) + (1-beta)*s.trend
+		s.season[i] = gamma*(ys[t]-level) + (1-gamma)*s.season[i]
+		s.level = level
+	}
+	if holidays > 0 {
+		s.holiday = holidaySum / float64(holidays)
+	}
+	if ssTot > 0 {
+		s.r2 = 1 - sse/ssTot
+	}
+	return s, sse
+}
+
+// forecast returns the value h days after the last observation, whose
+// offset from the start is last.
+func (s smoother) forecast(last, h int, holiday bool) float64 {
+	v := s.level + float64(h)*s.trend + s.season[(last+h)%len(s.season)]
+	if holiday {
+		v += s.holiday
+	}
+	return v
+}
+
+// Forecast returns the pods forecast for the day of date. Days within the
+// training data get their one-step-ahead fitted value, except the days that
+// initialized the state, which fail with model.ErrBeforeHistory.
+func (m *seasonalModel) Forecast(date time.Time) (model.FEPods, model.BEPods, error) {
+	cur := m.cur.Load()
+	if cur == nil {
+		return 0, 0, errors.New("model not trained")
+	}
+	d := day(date)
+	if d.Before(cur.start) {
+		return 0, 0, model.ErrBeforeHistory
+	}
+
+	var fe, be float64
+	last := int(cur.last.Sub(cur.start).Hours() / 24)
+	if h := int(d.Sub(cur.last).Hours() / 24); h > 0 {
+		holiday := m.holidays[d]
+		fe, be = cur.fe.forecast(last, h, holiday), cur.be.forecast(last, h, holiday)
+	} else {
+		t := last + h
+		if t < cur.fe.first {
+			return 0, 0, model.ErrBeforeHistory
+		}
+		fe, be = cur.fe.fitted[t], cur.be.fitted[t]
+	}
+	fePods, _, _ := model.ToPods(fe)
+	bePods, _, _ := model.ToPods(be)
+	return model.FEPods(fePods), model.BEPods(bePods), nil
+}
+
+// Predict fails with model.ErrNeedsDate: the model does not use features,
+// so pods can only be forecast for a date (see Forecast).
+func (m *seasonalModel) Predict(_ *model.Features) (model.FEPods, model.BEPods, error) {
+	return 0, 0, model.ErrNeedsDate
+}
+
+// Describe reports the smoothing factors and final state of each series.
+func (m *seasonalModel) Describe() (model.Description, error) {
+	cur := m.cur.Load()
+	if cur == nil {
+		return model.Description{}, errors.New("model not trained")
+	}
+	return model.Description{
+		Type:         "holtwinters",
+		TrainingRows: cur.rows,
+		TrainedAt:    cur.trainedAt,
+		FE:           cur.fe.describe(),
+		BE:           cur.be.describe(),
+	}, nil
+}
+
+func (s smoother) describe() model.Regressor {
+	return model.Regressor{
+		Intercept: s.level,
+		R2:        s.r2,
+		Params: map[string]float64{
+			"alpha":   s.alpha,
+			"beta":    s.beta,
+			"gamma":   s.gamma,
+			"trend":   s.trend,
+			"holiday": s.holiday,
+		},
+	}
+}
@@ -0,0 +1,129 @@
+package seasonal
+
+import (
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"github.com/thisiscetin/podpredict/internal/metrics"
+	"github.com/thisiscetin/podpredict/internal/model"
+)
+
+var (
+	start  = time.Date(2025, 1, 6, 0, 0, 0, 0, time.UTC) // a Monday
+	weekly = []float64{0, 2, 4, 4, 6, 12, 10}            // Monday to Sunday
+)
+
+// pods follows a trend of 0.2 pods a day plus the weekly pattern.
+func pods(t int) int {
+	return int(20 + 0.2*float64(t) + weekly[t%7] + 0.5)
+}
+
+func row(t int, fe int) metrics.Daily {
+	be := fe / 2
+	d, err := metrics.NewDaily(start.AddDate(0, 0, t), 1, 1, 1, &fe, &be)
+	if err != nil {
+		panic(err)
+	}
+	return d
+}
+
+func TestSeasonal_ForecastsTrendAndWeeklySeason(t *testing.T) {
+	var rows []metrics.Daily
+	for i := 0; i < 70; i++ {
+		if i == 30 || i == 31 {
+			continue // gaps are interpolated
+		}
+		rows = append(rows, row(i, pods(i)))
+	}
+	// Rows without pods, e.g. future days in the sheet, are not trained on.
+	future, err := metrics.NewDaily(start.AddDate(0, 0, 75), 1, 1, 1, nil, nil)
+	require.NoError(t, err)
+	rows = append(rows, future)
+
+	m := NewModel(Options{})
+	_, _, err = m.(model.Forecaster).Forecast(start)
+	require.Error(t, err, "forecast before train must fail")
+	require.NoError(t, m.Train(rows))
+
+	f := m.(model.Forecaster)
+	for h := 70; h < 84; h++ {
+		fe, be, err := f.Forecast(start.AddDate(0, 0, h).Add(15 * time.Hour))
+		require.NoError(t, err)
+		assert.InDelta(t, pods(h), int(fe), 1, "day %d", h)
+		assert.InDelta(t, pods(h)/2, int(be), 1, "day %d", h)
+	}
+
+	// In-sample days get their one-step-ahead fit; the first period only
+	// initializes the state, and it and earlier days fail.
+	fe, _, err := f.Forecast(start.AddDate(0, 0, 60))
+	require.NoError(t, err)
+	assert.InDelta(t, pods(60), int(fe), 1)
+	_, _, err = f.Forecast(start.AddDate(0, 0, 7))
+	require.NoError(t, err)
+	for _, d := range []int{-1, 0, 6} {
+		_, _, err = f.Forecast(start.AddDate(0, 0, d))
+		assert.ErrorIs(t, err, model.ErrBeforeHistory, "day %d", d)
+	}
+
+	// Features alone cannot be forecast.
+	_, _, err = m.Predict(&model.Features{GMV: 1e9})
+	assert.ErrorIs(t, err, model.ErrNeedsDate)
+
+	d, err := m.(model.Describer).Describe()
+	require.NoError(t, err)
+	assert.Equal(t, "holtwinters", d.Type)
+	assert.Equal(t, 68, d.TrainingRows)
+	assert.Greater(t, d.FE.R2, 0.9)
+	assert.InDelta(t, 0.2, d.FE.Params["trend"], 0.05)
+}
+
+func TestSeasonal_HolidayEffect(t *testing.T) {
+	var holidays []time.Time
+	var rows []metrics.Daily
+	for i := 0; i < 63; i++ {
+		fe := pods(i)
+		if i%10 == 9 {
+			holidays = append(holidays, start.AddDate(0, 0, i))
+			fe += 15
+		}
+		rows = append(rows, row(i, fe))
+	}
+	nextHoliday := start.AddDate(0, 0, 65)
+	holidays = append(holidays, nextHoliday)
+
+	m := NewModel(Options{Holidays: holidays})
+	require.NoError(t, m.Train(rows))
+	f := m.(model.Forecaster)
+
+	fe, _, err := f.Forecast(nextHoliday)
+	require.NoError(t, err)
+	assert.InDelta(t, pods(65)+15, int(fe), 2)
+
+	fe, _, err = f.Forecast(nextHoliday.AddDate(0, 0, 1))
+	require.NoError(t, err)
+	assert.InDelta(t, pods(66), int(fe), 1)
+
+	d, err := m.(model.Describer).Describe()
+	require.NoError(t, err)
+	assert.InDelta(t, 15, d.FE.Params["holiday"], 2)
+}
+
+func TestSeasonal_ShortHistory(t *testing.T) {
+	m := NewModel(Options{})
+	require.Error(t, m.Train(nil))
+
+	require.NoError(t, m.Train([]metrics.Daily{row(0, 8)}))
+	fe, be, err := m.(model.Forecaster).Forecast(start.AddDate(0, 0, 5))
+	require.NoError(t, err)
+	assert.Equal(t, model.FEPods(8), fe)
+	assert.Equal(t, model.BEPods(4), be)
+
+	// Fewer than two periods: a linear trend without season.
+	require.NoError(t, m.Train([]metrics.Daily{row(0, 10), row(1, 12), row(2, 14), row(3, 16)}))
+	fe, _, err = m.(model.Forecaster).Forecast(start.AddDate(0, 0, 6))
+	require.NoError(t, err)
+	assert.Equal(t, model.FEPods(22), fe)
+}
@@ -0,0 +1,128 @@
+package model
+
+import (
+	"errors"
+	"sync/atomic"
+	"time"
+
+	"github.com/thisiscetin/podpredict/internal/metrics"
+)
+
+// FeatureNames names the inputs in the order of metrics.Daily.Features and
+// Features.Values. Callers must not modify it.
+var FeatureNames = []string{"GMV", "Users", "MarketingCost"}
+
+// ErrNotTrained is returned by models asked to predict or describe
+// themselves before their first successful Train.
+var ErrNotTrained = errors.New("model not trained")
+
+// TrainingSet holds the rows a model trains on: those with both FE and BE
+// pods.
+type TrainingSet struct {
+	// X holds the features of each row, in FeatureNames order.
+	X [][]float64
+	// FE and BE hold the pods of each row.
+	FE, BE []float64
+	// Profile summarizes X.
+	Profile Profile
+}
+
+// NewTrainingSet extracts the rows with both FE and BE pods, failing when
+// there are none.
+func NewTrainingSet(rows []metrics.Daily) (TrainingSet, error) {
+	var ts TrainingSet
+	for _, d := range rows {
+		if !d.HasPods() {
+			continue
+		}
+		fePods, bePods, _ := d.Pods()
+		ts.X = append(ts.X, d.Features())
+		ts.FE = append(ts.FE, float64(fePods))
+		ts.BE = append(ts.BE, float64(bePods))
+	}
+	if len(ts.X) == 0 {
+		return TrainingSet{}, errors.New("no valid rows with FE/BE pods")
+	}
+	ts.Profile = NewProfile(rows)
+	return ts, nil
+}
+
+// R2 is the coefficient of determination of predict on the rows xs with
+// targets y, 0 for a constant target.
+func R2(predict func(x []float64) float64, xs [][]float64, y []float64) float64 {
+	var mean float64
+	for _, v := range y {
+		mean += v / float64(len(y))
+	}
+	var ssRes, ssTot float64
+	for i, x := range xs {
+		d := y[i] - predict(x)
+		ssRes += d * d
+		ssTot += (y[i] - mean) * (y[i] - mean)
+	}
+	if ssTot == 0 {
+		return 0
+	}
+	return 1 - ssRes/ssTot
+}
+
+// Fitted is an immutable fit F of a model with the training set it was
+// fitted on.
+type Fitted[F any] struct {
+	Fit F
+	// Rows is the number of training rows.
+	Rows int
+	// TrainedAt is when Train finished, in UTC.
+	TrainedAt time.Time
+	// Profile summarizes the training features.
+	Profile Profile
+}
+
+// Describe returns the Description of a model of type typ with the FE and
+// BE regressors of the fit.
+func (f *Fitted[F]) Describe(typ string, fe, be Regressor) Description {
+	return Description{
+		Type:         typ,
+		TrainingRows: f.Rows,
+		TrainedAt:    f.TrainedAt,
+		FeatureStats: &f.Profile,
+		FE:           fe,
+		BE:           be,
+	}
+}
+
+// Latest holds the Fitted of the last successful Train of a model, nil
+// until then. Train builds the fit off to the side and stores it, so
+// readers see either the previous or the new fit, never a partial one.
+// Embedding it implements Profiler.
+type Latest[F any] struct {
+	p atomic.Pointer[Fitted[F]]
+}
+
+// Store swaps in fit, trained on ts.
+func (l *Latest[F]) Store(fit F, ts TrainingSet) {
+	l.p.Store(&Fitted[F]{
+		Fit:       fit,
+		Rows:      len(ts.X),
+		TrainedAt: time.Now().UTC(),
+		Profile:   ts.Profile,
+	})
+}
+
+// Load returns the current fit, or ErrNotTrained.
+func (l *Latest[F]) Load() (*Fitted[F], error) {
+	cur := l.p.Load()
+	if cur == nil {
+		return nil, ErrNotTrained
+	}
+	return cur, nil
+}
+
+// Profile reports the per-feature statistics of the current training set.
+func (l *Latest[F]) Profile() (Profile, error) {
+	cur, err := l.Load()
+	if err != nil {
+		return Profile{}, err
+	}
+	return cur.Profile, nil
+}
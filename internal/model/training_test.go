@@ -0,0 +1,67 @@
+package model
+
+import (
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"github.com/thisiscetin/podpredict/internal/metrics"
+)
+
+func TestNewTrainingSet(t *testing.T) {
+	day := time.Date(2025, 1, 1, 0, 0, 0, 0, time.UTC)
+	fe, be := 4, 2
+	withPods, err := metrics.NewDaily(day, 10, 20, 30, &fe, &be)
+	require.NoError(t, err)
+	withoutPods, err := metrics.NewDaily(day.AddDate(0, 0, 1), 1000, 20, 30, nil, nil)
+	require.NoError(t, err)
+
+	ts, err := NewTrainingSet([]metrics.Daily{withPods, withoutPods})
+	require.NoError(t, err)
+	assert.Equal(t, [][]float64{{10, 20, 30}}, ts.X)
+	assert.Equal(t, []float64{4}, ts.FE)
+	assert.Equal(t, []float64{2}, ts.BE)
+	assert.Equal(t, 10.0, ts.Profile.GMV.Max, "rows without pods are not profiled")
+
+	_, err = NewTrainingSet([]metrics.Daily{withoutPods})
+	assert.Error(t, err)
+}
+
+func TestR2(t *testing.T) {
+	xs := [][]float64{{1}, {2}, {3}}
+	identity := func(x []float64) float64 { return x[0] }
+	mean := func([]float64) float64 { return 2 }
+
+	assert.Equal(t, 1.0, R2(identity, xs, []float64{1, 2, 3}))
+	assert.Equal(t, 0.0, R2(mean, xs, []float64{1, 2, 3}))
+	assert.Equal(t, 0.0, R2(identity, xs, []float64{5, 5, 5}), "constant target")
+}
+
+func TestLatest(t *testing.T) {
+	var l Latest[string]
+	_, err := l.Load()
+	assert.ErrorIs(t, err, ErrNotTrained)
+	_, err = l.Profile()
+	assert.ErrorIs(t, err, ErrNotTrained)
+
+	ts := TrainingSet{X: [][]float64{{1, 2, 3}, {4, 5, 6}}, Profile: Profile{GMV: FeatureStats{Max: 4}}}
+	l.Store("fit", ts)
+	cur, err := l.Load()
+	require.NoError(t, err)
+	assert.Equal(t, "fit", cur.Fit)
+	assert.Equal(t, 2, cur.Rows)
+	assert.False(t, cur.TrainedAt.IsZero())
+
+	p, err := l.Profile()
+	require.NoError(t, err)
+	assert.Equal(t, ts.Profile, p)
+
+	d := cur.Describe("test", Regressor{Intercept: 1}, Regressor{Intercept: 2})
+	assert.Equal(t, "test", d.Type)
+	assert.Equal(t, 2, d.TrainingRows)
+	assert.Equal(t, &ts.Profile, d.FeatureStats)
+	assert.Equal(t, 1.0, d.FE.Intercept)
+	assert.Equal(t, 2.0, d.BE.Intercept)
+}
@@ -0,0 +1,140 @@
+// Package tree fits regression trees, the base learners of the gbt and
+// forest models.
+package tree
+
+import (
+	"math"
+	"math/rand"
+	"slices"
+)
+
+// Options limits the growth of a tree.
+type Options struct {
+	// MaxDepth is the maximum number of splits from the root to a leaf.
+	MaxDepth int
+	// MinLeaf is the minimum number of rows in a leaf, at least 1.
+	MinLeaf int
+	// MaxFeatures, when positive and below the number of features, is the
+	// size of the random feature subset drawn with Rand at every split.
+	MaxFeatures int
+	// Rand draws the feature subsets; required with MaxFeatures.
+	Rand *rand.Rand
+}
+
+// Node is a fitted regression tree. Leaves predict the mean target of
+// their training rows; inner nodes send rows with x[feature] <= threshold
+// left and the others right.
+type Node struct {
+	feature     int
+	threshold   float64
+	left, right *Node
+	value       float64
+}
+
+// Fit grows a tree on the rows idx of x and y, choosing at every node the
+// split that most reduces the squared error. Ties go to the lowest feature
+// and threshold, so fitting is deterministic for a given Rand.
+func Fit(x [][]float64, y []float64, idx []int, o Options) *Node {
+	o.MinLeaf = max(o.MinLeaf, 1)
+	return grow(x, y, idx, o, 0)
+}
+
+func grow(x [][]float64, y []float64, idx []int, o Options, depth int) *Node {
+	var sum float64
+	for _, i := range idx {
+		sum += y[i]
+	}
+	n := &Node{value: sum / float64(len(idx))}
+	if depth >= o.MaxDepth || len(idx) < 2*o.MinLeaf {
+		return n
+	}
+
+	feature, threshold, ok := bestSplit(x, y, idx, candidates(len(x[idx[0]]), o), o.MinLeaf, sum)
+	if !ok {
+		return n
+	}
+	var left, right []int
+	for _, i := range idx {
+		if x[i][feature] <= threshold {
+			left = append(left, i)
+		} else {
+			right = append(right, i)
+		}
+	}
+	n.feature, n.threshold = feature, threshold
+	n.left = grow(x, y, left, o, depth+1)
+	n.right = grow(x, y, right, o, depth+1)
+	return n
+}
+
+// candidates returns the features considered for a split, in ascending order.
+func candidates(p int, o Options) []int {
+	if o.MaxFeatures <= 0 || o.MaxFeatures >= p {
+		fs := make([]int, p)
+		for f := range fs {
+			fs[f] = f
+		}
+		return fs
+	}
+	fs := o.Rand.Perm(p)[:o.MaxFeatures]
+	slices.Sort(fs)
+	return fs
+}
+
+// bestSplit scans the candidate features in sorted order for the threshold with the
+// largest reduction of the squared error. ok is false when no split with
+// at least minLeaf rows per side reduces it.
+func bestSplit(x [][]float64, y []float64, idx, features []int, minLeaf int, sum float64) (feature int, threshold float64, ok bool) {
+	total := float64(len(idx))
+	base := sum * sum / total
+	bestGain := 1e-12 * math.Max(1, math.Abs(base))
+
+	sorted := slices.Clone(idx)
+	for _, f := range features {
+		slices.SortStableFunc(sorted, func(a, b int) int {
+			switch {
+			case x[a][f] < x[b][f]:
+				return -1
+			case x[a][f] > x[b][f]:
+				return 1
+			}
+			return 0
+		})
+
+		var leftSum float64
+		for k := 0; k < len(sorted)-1; k++ {
+			leftSum += y[sorted[k]]
+			nl := k + 1
+			lo, hi := x[sorted[k]][f], x[sorted[k+1]][f]
+			if nl < minLeaf || len(sorted)-nl < minLeaf || lo == hi {
+				continue
+			}
+			rightSum := sum - leftSum
+			gain := leftSum*leftSum/float64(nl) + rightSum*rightSum/(total-float64(nl)) - base
+			if gain > bestGain {
+				bestGain, feature, threshold, ok = gain, f, lo+(hi-lo)/2, true
+			}
+		}
+	}
+	return feature, threshold, ok
+}
+
+// Predict returns the value of the leaf x falls into.
+func (n *Node) Predict(x []float64) float64 {
+	for n.left != nil {
+		if x[n.feature] <= n.threshold {
+			n = n.left
+		} else {
+			n = n.right
+		}
+	}
+	return n.value
+}
+
+// Leaves counts the leaves of the tree.
+func (n *Node) Leaves() int {
+	if n.left == nil {
+		return 1
+	}
+	return n.left.Leaves() + n.right.Leaves()
+}
@@ -0,0 +1,68 @@
+package tree
+
+import (
+	"math/rand"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func indices(n int) []int {
+	idx := make([]int, n)
+	for i := range idx {
+		idx[i] = i
+	}
+	return idx
+}
+
+func TestFit_FindsStepInSecondFeature(t *testing.T) {
+	// y steps from 1 to 5 once x1 exceeds 10; x0 is noise.
+	x := [][]float64{{3, 2}, {1, 4}, {2, 8}, {5, 12}, {4, 14}, {0, 20}}
+	y := []float64{1, 1, 1, 5, 5, 5}
+
+	n := Fit(x, y, indices(len(x)), Options{MaxDepth: 3})
+	assert.Equal(t, 2, n.Leaves(), "a perfect split needs no further depth")
+	assert.Equal(t, 1, n.feature)
+	assert.Equal(t, 10.0, n.threshold)
+	assert.Equal(t, 1.0, n.Predict([]float64{9, 9}))
+	assert.Equal(t, 5.0, n.Predict([]float64{0, 11}))
+}
+
+func TestFit_RespectsLimits(t *testing.T) {
+	x := [][]float64{{1}, {2}, {3}, {4}, {5}, {6}, {7}, {8}}
+	y := []float64{1, 2, 3, 4, 5, 6, 7, 8}
+
+	assert.Equal(t, 1, Fit(x, y, indices(8), Options{MaxDepth: 0}).Leaves())
+	assert.Equal(t, 4, Fit(x, y, indices(8), Options{MaxDepth: 2}).Leaves())
+	assert.Equal(t, 8, Fit(x, y, indices(8), Options{MaxDepth: 10}).Leaves())
+	assert.Equal(t, 2, Fit(x, y, indices(8), Options{MaxDepth: 10, MinLeaf: 4}).Leaves())
+
+	root := Fit(x, y, indices(8), Options{MaxDepth: 1})
+	assert.Equal(t, 2.5, root.Predict([]float64{0}))
+	assert.Equal(t, 6.5, root.Predict([]float64{100}))
+}
+
+func TestFit_ConstantTargetIsALeaf(t *testing.T) {
+	x := [][]float64{{1}, {2}, {3}}
+	n := Fit(x, []float64{4, 4, 4}, indices(3), Options{MaxDepth: 5})
+	assert.Equal(t, 1, n.Leaves())
+	assert.Equal(t, 4.0, n.Predict([]float64{7}))
+}
+
+func TestFit_MaxFeaturesRestrictsSplits(t *testing.T) {
+	// Only x1 is informative; with one random feature per split, the root
+	// splits on x0 whenever x1 is not drawn.
+	x := [][]float64{{3, 2}, {1, 4}, {2, 8}, {5, 12}, {4, 14}, {0, 20}}
+	y := []float64{1, 1, 1, 5, 5, 5}
+
+	seen := map[int]bool{}
+	for seed := int64(0); seed < 20; seed++ {
+		n := Fit(x, y, indices(len(x)), Options{MaxDepth: 1, MaxFeatures: 1, Rand: rand.New(rand.NewSource(seed))})
+		seen[n.feature] = true
+	}
+	assert.Equal(t, map[int]bool{0: true, 1: true}, seen)
+
+	a := Fit(x, y, indices(len(x)), Options{MaxDepth: 3, MaxFeatures: 1, Rand: rand.New(rand.NewSource(5))})
+	b := Fit(x, y, indices(len(x)), Options{MaxDepth: 3, MaxFeatures: 1, Rand: rand.New(rand.NewSource(5))})
+	assert.Equal(t, a, b, "deterministic for a seed")
+}
@@ -8,40 +8,128 @@ import (
 )
 
 // impl is a concrete in-memory implementation of the store.Store interface.
-// It holds all predictions in a slice protected by a read/write mutex.
-// This type is safe for concurrent access by multiple goroutines.
+// It holds all predictions in a slice protected by a read/write mutex, with
+// an index from ID to slice position so that Get and Delete avoid a linear
+// scan. This type is safe for concurrent access by multiple goroutines.
 type impl struct {
 	predictions []store.Prediction
+	index       map[string]int // ID → position in predictions
 	sync.RWMutex
 }
 
 // Append adds the given prediction to the store.
 // It acquires a write lock to ensure thread safety, appends the prediction
-// to the internal slice, and releases the lock. Append always returns nil
-// unless the method’s signature changes to support future error handling.
+// to the internal slice, and releases the lock. Append fails with
+// store.ErrExists if the ID is already stored.
 // Append is safe for concurrent use.
 func (i *impl) Append(_ context.Context, r store.Prediction) error {
 	i.Lock()
 	defer i.Unlock()
 
+	if _, ok := i.index[r.ID]; ok {
+		return store.ErrExists
+	}
+	i.index[r.ID] = len(i.predictions)
 	i.predictions = append(i.predictions, r)
 	return nil
 }
 
-// List returns a copy of all stored Predictions.
+// AppendBatch adds all of rs under a single write lock, or none of them
+// if any ID is already stored or repeated within rs.
+// The provided context is currently unused but is accepted to
+// satisfy the store.Store interface.
+func (i *impl) AppendBatch(_ context.Context, rs []store.Prediction) error {
+	i.Lock()
+	defer i.Unlock()
+
+	seen := make(map[string]struct{}, len(rs))
+	for _, r := range rs {
+		_, stored := i.index[r.ID]
+		_, repeated := seen[r.ID]
+		if stored || repeated {
+			return store.ErrExists
+		}
+		seen[r.ID] = struct{}{}
+	}
+	for _, r := range rs {
+		i.index[r.ID] = len(i.predictions)
+		i.predictions = append(i.predictions, r)
+	}
+	return nil
+}
+
+// Upsert stores r under the ID derived from its natural key, replacing any
+// prediction already stored under that ID in place.
+// The provided context is currently unused but is accepted to
+// satisfy the store.Store interface.
+func (i *impl) Upsert(_ context.Context, r store.Prediction) error {
+	r, err := store.PrepareUpsert(r)
+	if err != nil {
+		return err
+	}
+
+	i.Lock()
+	defer i.Unlock()
+
+	if pos, ok := i.index[r.ID]; ok {
+		i.predictions[pos] = r
+		return nil
+	}
+	i.index[r.ID] = len(i.predictions)
+	i.predictions = append(i.predictions, r)
+	return nil
+}
+
+// Get returns the prediction with the given ID, or store.ErrNotFound.
+// The provided context is currently unused but is accepted to
+// satisfy the store.Store interface.
+func (i *impl) Get(_ context.Context, id string) (store.Prediction, error) {
+	i.RLock()
+	defer i.RUnlock()
+
+	pos, ok := i.index[id]
+	if !ok {
+		return store.Prediction{}, store.ErrNotFound
+	}
+	return i.predictions[pos], nil
+}
+
+// Delete removes the prediction with the given ID, or returns
+// store.ErrNotFound. The last prediction is moved into the freed slot,
+// which is fine because List orders results itself.
+// The provided context is currently unused but is accepted to
+// satisfy the store.Store interface.
+func (i *impl) Delete(_ context.Context, id string) error {
+	i.Lock()
+	defer i.Unlock()
+
+	pos, ok := i.index[id]
+	if !ok {
+		return store.ErrNotFound
+	}
+	last := len(i.predictions) - 1
+	if pos != last {
+		i.predictions[pos] = i.predictions[last]
+		i.index[i.predictions[pos].ID] = pos
+	}
+	i.predictions[last] = store.Prediction{}
+	i.predictions = i.predictions[:last]
+	delete(i.index, id)
+	return nil
+}
+
+// List returns a copy of the predictions selected by q.
 // The returned slice is a defensive copy of the internal state,
 // meaning callers can modify it freely without affecting the
-// underlying store. Order of elements corresponds to the order
-// in which they were appended.
+// underlying store. Filtering and pagination run over the whole
+// slice under a read lock.
 // The provided context is currently unused but is accepted to
 // satisfy the store.Store interface.
-func (i *impl) List(_ context.Context) ([]store.Prediction, error) {
+func (i *impl) List(_ context.Context, q store.Query) (store.Page, error) {
 	i.RLock()
 	defer i.RUnlock()
 
-	out := make([]store.Prediction, len(i.predictions))
-	copy(out, i.predictions)
-	return out, nil
+	return store.Apply(i.predictions, q)
 }
 
 // NewStore creates and returns a new, empty in-memory store.
@@ -51,5 +139,6 @@ func (i *impl) List(_ context.Context) ([]store.Prediction, error) {
 func NewStore() store.Store {
 	return &impl{
 		predictions: make([]store.Prediction, 0),
+		index:       make(map[string]int),
 	}
 }
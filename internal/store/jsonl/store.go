@@ -0,0 +1,524 @@
+// Package jsonl implements store.Store as an append-only file of JSON lines,
+// for small deployments that want persistence without a database.
+//
+// Each Append writes one store.Prediction as a JSON line and fsyncs the file
+// before returning. Delete appends a tombstone line {"id":…,"deleted":true}.
+// AppendBatch writes a header line {"batch":n} before the n records of the
+// batch. NewStore replays the file, truncating a torn last line, or a batch
+// missing some of its n records, left behind by a crash. Once the active file
+// exceeds Options.MaxSize it is rotated: all live predictions are compacted
+// into a sealed segment (<path>.<n>, written to a temporary file, fsynced and
+// renamed into place), older segments are removed and appends continue on an
+// empty active file. A write that succeeded is not failed by its rotation: a
+// rotation error is logged and rotation is retried on the next write.
+// NewStore removes temporary files left by a crash.
+package jsonl
+
+import (
+	"bufio"
+	"bytes"
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"log"
+	"os"
+	"path/filepath"
+	"sort"
+	"strconv"
+	"strings"
+	"sync"
+
+	"github.com/thisiscetin/podpredict/internal/store"
+)
+
+// record is one line of the file: a prediction, a tombstone for its ID, or
+// the header of a batch of Batch records.
+type record struct {
+	store.Prediction
+	Deleted bool `json:"deleted,omitempty"`
+	Batch   int  `json:"batch,omitempty"`
+}
+
+// batchHeader is the on-disk form of the line that opens a batch.
+type batchHeader struct {
+	Batch int `json:"batch"`
+}
+
+// Options configures a file store.
+type Options struct {
+	// MaxSize is the size in bytes of the active file that triggers
+	// compaction and rotation. 0 never rotates.
+	MaxSize int64
+	// Logf reports rotation failures, which are retried on the next write.
+	// Defaults to log.Printf.
+	Logf func(format string, args ...any)
+}
+
+func (o Options) withDefaults() Options {
+	if o.Logf == nil {
+		o.Logf = log.Printf
+	}
+	return o
+}
+
+// impl is a file-backed implementation of the store.Store interface.
+// All predictions are also kept in memory to serve List.
+// It is safe for concurrent use.
+type impl struct {
+	path string
+	opts Options
+
+	mu          sync.RWMutex
+	f           *os.File // active file, opened for appending
+	size        int64    // size of the active file
+	segment     int      // number of the newest sealed segment, 0 if none
+	predictions []store.Prediction
+	index       map[string]int // ID → position in predictions
+}
+
+// NewStore opens the store at path, creating it if necessary, and replays
+// the sealed segments and the active file. A torn last line of the active
+// file is truncated; any other malformed line is an error.
+// The returned store also implements io.Closer; call Close on shutdown.
+func NewStore(path string, opts Options) (store.Store, error) {
+	s := &impl{
+		path:        path,
+		opts:        opts.withDefaults(),
+		predictions: make([]store.Prediction, 0),
+		index:       make(map[string]int),
+	}
+
+	if err := s.removeTemp(); err != nil {
+		return nil, err
+	}
+	segments, err := s.segments()
+	if err != nil {
+		return nil, err
+	}
+	for _, n := range segments {
+		if _, err := s.replay(s.segmentPath(n), false); err != nil {
+			return nil, err
+		}
+		s.segment = n
+	}
+
+	size, err := s.replay(path, true)
+	if err != nil {
+		return nil, err
+	}
+	f, err := os.OpenFile(path, os.O_CREATE|os.O_WRONLY|os.O_APPEND, 0o644)
+	if err != nil {
+		return nil, err
+	}
+	s.f, s.size = f, size
+	return s, nil
+}
+
+// Append writes r as a JSON line and fsyncs the file before returning.
+// IDs must be unique.
+func (s *impl) Append(ctx context.Context, r store.Prediction) error {
+	if err := ctx.Err(); err != nil {
+		return err
+	}
+	line, err := json.Marshal(r)
+	if err != nil {
+		return err
+	}
+	line = append(line, '\n')
+
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	if _, ok := s.index[r.ID]; ok {
+		return store.ErrExists
+	}
+	if err := s.write(line); err != nil {
+		return err
+	}
+	s.add(r)
+	s.maybeRotate()
+	return nil
+}
+
+// AppendBatch writes a batch header and rs as consecutive JSON lines with a
+// single write and fsync, or nothing if any ID is already stored or repeated
+// within rs. Replay drops a batch cut short by a crash as a whole.
+func (s *impl) AppendBatch(ctx context.Context, rs []store.Prediction) error {
+	if err := ctx.Err(); err != nil {
+		return err
+	}
+	var buf bytes.Buffer
+	enc := json.NewEncoder(&buf)
+	if err := enc.Encode(batchHeader{Batch: len(rs)}); err != nil {
+		return err
+	}
+	for _, r := range rs {
+		if err := enc.Encode(r); err != nil {
+			return err
+		}
+	}
+
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	seen := make(map[string]struct{}, len(rs))
+	for _, r := range rs {
+		_, stored := s.index[r.ID]
+		_, repeated := seen[r.ID]
+		if stored || repeated {
+			return store.ErrExists
+		}
+		seen[r.ID] = struct{}{}
+	}
+	if len(rs) == 0 {
+		return nil
+	}
+	if err := s.write(buf.Bytes()); err != nil {
+		return err
+	}
+	for _, r := range rs {
+		s.add(r)
+	}
+	s.maybeRotate()
+	return nil
+}
+
+// Upsert appends r under the ID derived from its natural key and fsyncs the
+// file. On replay, the last line for an ID replaces earlier ones.
+func (s *impl) Upsert(ctx context.Context, r store.Prediction) error {
+	if err := ctx.Err(); err != nil {
+		return err
+	}
+	r, err := store.PrepareUpsert(r)
+	if err != nil {
+		return err
+	}
+	line, err := json.Marshal(r)
+	if err != nil {
+		return err
+	}
+	line = append(line, '\n')
+
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	if err := s.write(line); err != nil {
+		return err
+	}
+	s.add(r)
+	s.maybeRotate()
+	return nil
+}
+
+// Get returns the prediction with the given ID, or store.ErrNotFound.
+func (s *impl) Get(ctx context.Context, id string) (store.Prediction, error) {
+	if err := ctx.Err(); err != nil {
+		return store.Prediction{}, err
+	}
+	s.mu.RLock()
+	defer s.mu.RUnlock()
+
+	pos, ok := s.index[id]
+	if !ok {
+		return store.Prediction{}, store.ErrNotFound
+	}
+	return s.predictions[pos], nil
+}
+
+// Delete appends a tombstone for id and fsyncs the file, or returns
+// store.ErrNotFound. The record itself disappears at the next compaction.
+func (s *impl) Delete(ctx context.Context, id string) error {
+	if err := ctx.Err(); err != nil {
+		return err
+	}
+	line, err := json.Marshal(tombstone{ID: id, Deleted: true})
+	if err != nil {
+		return err
+	}
+	line = append(line, '\n')
+
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	if _, ok := s.index[id]; !ok {
+		return store.ErrNotFound
+	}
+	if err := s.write(line); err != nil {
+		return err
+	}
+	s.remove(id)
+	s.maybeRotate()
+	return nil
+}
+
+// tombstone is the on-disk form of a deleted record.
+type tombstone struct {
+	ID      string `json:"id"`
+	Deleted bool   `json:"deleted"`
+}
+
+// List returns a copy of the predictions selected by q.
+func (s *impl) List(ctx context.Context, q store.Query) (store.Page, error) {
+	if err := ctx.Err(); err != nil {
+		return store.Page{}, err
+	}
+	s.mu.RLock()
+	defer s.mu.RUnlock()
+
+	return store.Apply(s.predictions, q)
+}
+
+// Compact rewrites all live predictions into a single sealed segment and
+// starts a new, empty active file.
+func (s *impl) Compact() error {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	return s.rotate()
+}
+
+// Close closes the active file.
+func (s *impl) Close() error {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	return s.f.Close()
+}
+
+// write appends line to the active file and fsyncs it. On a short write the
+// file is truncated back so that a later append does not follow a torn line.
+func (s *impl) write(line []byte) error {
+	n, err := s.f.Write(line)
+	if err == nil {
+		err = s.f.Sync()
+	}
+	if err != nil {
+		if n > 0 {
+			_ = s.f.Truncate(s.size)
+		}
+		return err
+	}
+	s.size += int64(n)
+	return nil
+}
+
+// add records r in memory. A later record with the same ID replaces the
+// earlier one in place. This applies upserts on replay and keeps replay
+// idempotent if a crash leaves records in both a new segment and the
+// files it was compacted from.
+func (s *impl) add(r store.Prediction) {
+	if i, ok := s.index[r.ID]; ok {
+		s.predictions[i] = r
+		return
+	}
+	s.index[r.ID] = len(s.predictions)
+	s.predictions = append(s.predictions, r)
+}
+
+// remove drops id from memory, moving the last prediction into its slot.
+func (s *impl) remove(id string) {
+	pos, ok := s.index[id]
+	if !ok {
+		return
+	}
+	last := len(s.predictions) - 1
+	if pos != last {
+		s.predictions[pos] = s.predictions[last]
+		s.index[s.predictions[pos].ID] = pos
+	}
+	s.predictions[last] = store.Prediction{}
+	s.predictions = s.predictions[:last]
+	delete(s.index, id)
+}
+
+// maybeRotate rotates once the active file exceeds Options.MaxSize. It runs
+// after a write has been applied, so a failure is only logged; the file stays
+// over MaxSize and the next write tries again.
+func (s *impl) maybeRotate() {
+	if s.opts.MaxSize > 0 && s.size > s.opts.MaxSize {
+		if err := s.rotate(); err != nil {
+			s.opts.Logf("jsonl: rotating %s: %v (retrying on next write)", s.path, err)
+		}
+	}
+}
+
+// rotate compacts all live predictions into sealed segment s.segment+1,
+// dropping deleted ones and their tombstones,
+// removes older segments and truncates the active file. Each step leaves a
+// replayable state if the process crashes before the next one.
+func (s *impl) rotate() error {
+	next := s.segment + 1
+	if err := s.writeSegment(next); err != nil {
+		return fmt.Errorf("compacting %s: %w", s.path, err)
+	}
+	for n := s.segment; n > 0; n-- {
+		if err := os.Remove(s.segmentPath(n)); err != nil && !errors.Is(err, os.ErrNotExist) {
+			return err
+		}
+	}
+	s.segment = next
+
+	if err := s.f.Truncate(0); err != nil {
+		return err
+	}
+	if err := s.f.Sync(); err != nil {
+		return err
+	}
+	s.size = 0
+	return nil
+}
+
+// writeSegment atomically writes the in-memory predictions to segment n.
+func (s *impl) writeSegment(n int) error {
+	tmp, err := os.CreateTemp(filepath.Dir(s.path), filepath.Base(s.path)+".tmp-*")
+	if err != nil {
+		return err
+	}
+	defer os.Remove(tmp.Name()) // no-op after a successful rename
+
+	w := bufio.NewWriter(tmp)
+	enc := json.NewEncoder(w)
+	for _, p := range s.predictions {
+		if err := enc.Encode(p); err != nil {
+			_ = tmp.Close()
+			return err
+		}
+	}
+	if err := w.Flush(); err != nil {
+		_ = tmp.Close()
+		return err
+	}
+	if err := tmp.Sync(); err != nil {
+		_ = tmp.Close()
+		return err
+	}
+	if err := tmp.Close(); err != nil {
+		return err
+	}
+	if err := os.Rename(tmp.Name(), s.segmentPath(n)); err != nil {
+		return err
+	}
+	return syncDir(filepath.Dir(s.path))
+}
+
+// replay loads the JSON lines of the file at name into memory and returns
+// the number of valid bytes. A missing file is empty. When active is set,
+// a torn or undecodable last line, and the incomplete batch it belongs to,
+// are truncated away.
+func (s *impl) replay(name string, active bool) (int64, error) {
+	data, err := os.ReadFile(name)
+	if errors.Is(err, os.ErrNotExist) {
+		return 0, nil
+	}
+	if err != nil {
+		return 0, err
+	}
+
+	var (
+		offset int64    // end of the last complete line
+		valid  int64    // end of the last applied line or complete batch
+		batch  []record // records of the open batch
+		want   int      // size of the open batch, 0 if none
+	)
+	for lineNum := 1; len(data) > 0; lineNum++ {
+		line, rest, complete := bytes.Cut(data, []byte{'\n'})
+		var rec record
+		err := json.Unmarshal(line, &rec)
+		if err == nil && !complete {
+			err = io.ErrUnexpectedEOF
+		}
+		if err == nil && rec.Batch > 0 && want > 0 {
+			err = errors.New("batch header inside a batch")
+		}
+		if err != nil {
+			if active && len(rest) == 0 {
+				// Torn write from a crash: drop it with its batch.
+				return valid, os.Truncate(name, valid)
+			}
+			return 0, fmt.Errorf("%s:%d: %w", name, lineNum, err)
+		}
+		offset += int64(len(line)) + 1
+		data = rest
+
+		switch {
+		case rec.Batch > 0:
+			batch, want = make([]record, 0, rec.Batch), rec.Batch
+			continue
+		case want > 0:
+			if batch = append(batch, rec); len(batch) < want {
+				continue
+			}
+			for _, r := range batch {
+				s.apply(r)
+			}
+			batch, want = nil, 0
+		default:
+			s.apply(rec)
+		}
+		valid = offset
+	}
+	if want > 0 {
+		if !active {
+			return 0, fmt.Errorf("%s: %w: batch of %d records ends after %d",
+				name, io.ErrUnexpectedEOF, want, len(batch))
+		}
+		// Batch cut short by a crash: drop all of it.
+		return valid, os.Truncate(name, valid)
+	}
+	return valid, nil
+}
+
+// apply replays one record.
+func (s *impl) apply(rec record) {
+	if rec.Deleted {
+		s.remove(rec.ID)
+	} else {
+		s.add(rec.Prediction)
+	}
+}
+
+// removeTemp removes the temporary segment files of rotations interrupted
+// by a crash.
+func (s *impl) removeTemp() error {
+	matches, err := filepath.Glob(s.path + ".tmp-*")
+	if err != nil {
+		return err
+	}
+	for _, m := range matches {
+		if err := os.Remove(m); err != nil && !errors.Is(err, os.ErrNotExist) {
+			return err
+		}
+	}
+	return nil
+}
+
+// segments returns the numbers of the sealed segments on disk, ascending.
+func (s *impl) segments() ([]int, error) {
+	matches, err := filepath.Glob(s.path + ".*")
+	if err != nil {
+		return nil, err
+	}
+	var out []int
+	for _, m := range matches {
+		n, err := strconv.Atoi(strings.TrimPrefix(m, s.path+"."))
+		if err == nil && n > 0 {
+			out = append(out, n)
+		}
+	}
+	sort.Ints(out)
+	return out, nil
+}
+
+func (s *impl) segmentPath(n int) string {
+	return s.path + "." + strconv.Itoa(n)
+}
+
+// syncDir fsyncs a directory so that a rename within it is durable.
+func syncDir(dir string) error {
+	d, err := os.Open(dir)
+	if err != nil {
+		return err
+	}
+	defer d.Close()
+	return d.Sync()
+}
@@ -0,0 +1,283 @@
+package jsonl_test
+
+import (
+	"bytes"
+	"context"
+	"io"
+	"os"
+	"path/filepath"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"github.com/thisiscetin/podpredict/internal/store"
+	"github.com/thisiscetin/podpredict/internal/store/jsonl"
+	"github.com/thisiscetin/podpredict/internal/store/storetest"
+)
+
+func open(t *testing.T, path string, opts jsonl.Options) store.Store {
+	t.Helper()
+	s, err := jsonl.NewStore(path, opts)
+	require.NoError(t, err)
+	t.Cleanup(func() { _ = s.(io.Closer).Close() })
+	return s
+}
+
+func TestConformance(t *testing.T) {
+	storetest.Run(t, func(t *testing.T) store.Store {
+		return open(t, filepath.Join(t.TempDir(), "predictions.jsonl"), jsonl.Options{})
+	})
+}
+
+func TestConformance_Rotating(t *testing.T) {
+	storetest.Run(t, func(t *testing.T) store.Store {
+		return open(t, filepath.Join(t.TempDir(), "predictions.jsonl"), jsonl.Options{MaxSize: 512})
+	})
+}
+
+func TestReplay(t *testing.T) {
+	path := filepath.Join(t.TempDir(), "predictions.jsonl")
+	ctx := context.Background()
+
+	s := open(t, path, jsonl.Options{})
+	require.NoError(t, s.Append(ctx, storetest.Pred(1)))
+	require.NoError(t, s.Append(ctx, storetest.Pred(2)))
+	require.NoError(t, s.(io.Closer).Close())
+
+	got, err := storetest.All(ctx, open(t, path, jsonl.Options{}))
+	require.NoError(t, err)
+	assert.Equal(t, []store.Prediction{storetest.Pred(1), storetest.Pred(2)}, got)
+}
+
+func TestReplay_TruncatesTornLastLine(t *testing.T) {
+	path := filepath.Join(t.TempDir(), "predictions.jsonl")
+	ctx := context.Background()
+
+	s := open(t, path, jsonl.Options{})
+	require.NoError(t, s.Append(ctx, storetest.Pred(1)))
+	require.NoError(t, s.(io.Closer).Close())
+	good, err := os.Stat(path)
+	require.NoError(t, err)
+
+	// Simulate a crash in the middle of writing the second line.
+	f, err := os.OpenFile(path, os.O_WRONLY|os.O_APPEND, 0)
+	require.NoError(t, err)
+	_, err = f.WriteString(`{"id":"torn","timestamp":"2025-`)
+	require.NoError(t, err)
+	require.NoError(t, f.Close())
+
+	s = open(t, path, jsonl.Options{})
+	got, err := storetest.All(ctx, s)
+	require.NoError(t, err)
+	assert.Equal(t, []store.Prediction{storetest.Pred(1)}, got)
+
+	fi, err := os.Stat(path)
+	require.NoError(t, err)
+	assert.Equal(t, good.Size(), fi.Size(), "torn line should be truncated")
+
+	// Appends continue on a clean line boundary.
+	require.NoError(t, s.Append(ctx, storetest.Pred(2)))
+	require.NoError(t, s.(io.Closer).Close())
+	got, err = storetest.All(ctx, open(t, path, jsonl.Options{}))
+	require.NoError(t, err)
+	assert.Equal(t, []store.Prediction{storetest.Pred(1), storetest.Pred(2)}, got)
+}
+
+func TestReplay_CorruptLineInTheMiddleFails(t *testing.T) {
+	path := filepath.Join(t.TempDir(), "predictions.jsonl")
+	require.NoError(t, os.WriteFile(path, []byte("not json\n{}\n"), 0o644))
+
+	_, err := jsonl.NewStore(path, jsonl.Options{})
+	assert.ErrorContains(t, err, "predictions.jsonl:1")
+}
+
+func TestRotation(t *testing.T) {
+	dir := t.TempDir()
+	path := filepath.Join(dir, "predictions.jsonl")
+	ctx := context.Background()
+
+	s := open(t, path, jsonl.Options{MaxSize: 600})
+	var want []store.Prediction
+	for i := 0; i < 10; i++ {
+		require.NoError(t, s.Append(ctx, storetest.Pred(i)))
+		want = append(want, storetest.Pred(i))
+	}
+
+	fi, err := os.Stat(path)
+	require.NoError(t, err)
+	assert.LessOrEqual(t, fi.Size(), int64(600), "active file should have been rotated")
+
+	// Only the newest compacted segment is kept.
+	segments, err := filepath.Glob(path + ".*")
+	require.NoError(t, err)
+	assert.Len(t, segments, 1)
+
+	require.NoError(t, s.(io.Closer).Close())
+	got, err := storetest.All(ctx, open(t, path, jsonl.Options{MaxSize: 600}))
+	require.NoError(t, err)
+	assert.Equal(t, want, got)
+}
+
+func TestCompact(t *testing.T) {
+	path := filepath.Join(t.TempDir(), "predictions.jsonl")
+	ctx := context.Background()
+
+	s := open(t, path, jsonl.Options{})
+	require.NoError(t, s.Append(ctx, storetest.Pred(1)))
+	require.NoError(t, s.(interface{ Compact() error }).Compact())
+	require.NoError(t, s.Append(ctx, storetest.Pred(2)))
+
+	_, err := os.Stat(path + ".1")
+	require.NoError(t, err, "compaction should write segment 1")
+
+	require.NoError(t, s.(io.Closer).Close())
+	got, err := storetest.All(ctx, open(t, path, jsonl.Options{}))
+	require.NoError(t, err)
+	assert.Equal(t, []store.Prediction{storetest.Pred(1), storetest.Pred(2)}, got)
+}
+
+func TestReplay_DeduplicatesAfterInterruptedCompaction(t *testing.T) {
+	path := filepath.Join(t.TempDir(), "predictions.jsonl")
+	ctx := context.Background()
+
+	s := open(t, path, jsonl.Options{})
+	require.NoError(t, s.Append(ctx, storetest.Pred(1)))
+	require.NoError(t, s.Append(ctx, storetest.Pred(2)))
+	require.NoError(t, s.(io.Closer).Close())
+
+	// A crash after writing the segment but before truncating the active file
+	// leaves every record on disk twice.
+	data, err := os.ReadFile(path)
+	require.NoError(t, err)
+	require.NoError(t, os.WriteFile(path+".1", data, 0o644))
+
+	got, err := storetest.All(ctx, open(t, path, jsonl.Options{}))
+	require.NoError(t, err)
+	assert.Equal(t, []store.Prediction{storetest.Pred(1), storetest.Pred(2)}, got)
+}
+
+func TestDelete_PersistsAndCompactsAway(t *testing.T) {
+	path := filepath.Join(t.TempDir(), "predictions.jsonl")
+	ctx := context.Background()
+
+	s := open(t, path, jsonl.Options{})
+	require.NoError(t, s.Append(ctx, storetest.Pred(1)))
+	require.NoError(t, s.Append(ctx, storetest.Pred(2)))
+	require.NoError(t, s.Delete(ctx, storetest.Pred(1).ID))
+	require.NoError(t, s.(io.Closer).Close())
+
+	s = open(t, path, jsonl.Options{})
+	got, err := storetest.All(ctx, s)
+	require.NoError(t, err)
+	assert.Equal(t, []store.Prediction{storetest.Pred(2)}, got, "tombstone should be replayed")
+
+	require.NoError(t, s.(interface{ Compact() error }).Compact())
+	data, err := os.ReadFile(path + ".1")
+	require.NoError(t, err)
+	assert.NotContains(t, string(data), storetest.Pred(1).ID, "compaction should drop deleted records")
+	assert.NotContains(t, string(data), "deleted")
+}
+
+func TestUpsert_ReplaysLastWrite(t *testing.T) {
+	path := filepath.Join(t.TempDir(), "predictions.jsonl")
+	ctx := context.Background()
+
+	p := storetest.Pred(1)
+	p.Source, p.Kind = store.SourceSheet, store.KindActual
+
+	s := open(t, path, jsonl.Options{})
+	require.NoError(t, s.Upsert(ctx, p))
+	p.FEPods = 42
+	require.NoError(t, s.Upsert(ctx, p))
+	require.NoError(t, s.(io.Closer).Close())
+
+	got, err := storetest.All(ctx, open(t, path, jsonl.Options{}))
+	require.NoError(t, err)
+	require.Len(t, got, 1)
+	assert.Equal(t, 42, got[0].FEPods)
+}
+
+func TestRotationFailure_DoesNotFailTheWrite(t *testing.T) {
+	path := filepath.Join(t.TempDir(), "predictions.jsonl")
+	ctx := context.Background()
+
+	var logged []string
+	s := open(t, path, jsonl.Options{MaxSize: 100, Logf: func(format string, args ...any) {
+		logged = append(logged, format)
+	}})
+	// A directory in the way of segment 1 makes the rename fail.
+	require.NoError(t, os.Mkdir(path+".1", 0o755))
+
+	require.NoError(t, s.Append(ctx, storetest.Pred(1)))
+	require.Len(t, logged, 1)
+	_, err := s.Get(ctx, storetest.Pred(1).ID)
+	require.NoError(t, err, "the write was applied")
+	assert.ErrorIs(t, s.Append(ctx, storetest.Pred(1)), store.ErrExists)
+
+	// The next write retries the rotation.
+	require.NoError(t, os.Remove(path+".1"))
+	require.NoError(t, s.Append(ctx, storetest.Pred(2)))
+	assert.Len(t, logged, 1)
+	_, err = os.Stat(path + ".1")
+	require.NoError(t, err, "rotation should have been retried")
+
+	require.NoError(t, s.(io.Closer).Close())
+	got, err := storetest.All(ctx, open(t, path, jsonl.Options{}))
+	require.NoError(t, err)
+	assert.Equal(t, []store.Prediction{storetest.Pred(1), storetest.Pred(2)}, got)
+}
+
+func TestNewStore_RemovesTemporaryFiles(t *testing.T) {
+	path := filepath.Join(t.TempDir(), "predictions.jsonl")
+	tmp := path + ".tmp-123"
+	require.NoError(t, os.WriteFile(tmp, []byte(`{"id":"x"`), 0o644))
+
+	open(t, path, jsonl.Options{})
+	_, err := os.Stat(tmp)
+	assert.ErrorIs(t, err, os.ErrNotExist)
+}
+
+func TestReplay_DropsIncompleteBatch(t *testing.T) {
+	for name, cut := range map[string]func(data []byte) []byte{
+		// Crash between the writes of the last two records.
+		"missing record": func(data []byte) []byte {
+			return data[:bytes.LastIndexByte(data[:len(data)-1], '\n')+1]
+		},
+		"torn record": func(data []byte) []byte { return data[:len(data)-10] },
+	} {
+		t.Run(name, func(t *testing.T) {
+			path := filepath.Join(t.TempDir(), "predictions.jsonl")
+			ctx := context.Background()
+
+			s := open(t, path, jsonl.Options{})
+			require.NoError(t, s.Append(ctx, storetest.Pred(1)))
+			require.NoError(t, s.(io.Closer).Close())
+			good, err := os.Stat(path)
+			require.NoError(t, err)
+
+			s = open(t, path, jsonl.Options{})
+			require.NoError(t, s.AppendBatch(ctx, []store.Prediction{storetest.Pred(2), storetest.Pred(3)}))
+			require.NoError(t, s.(io.Closer).Close())
+			data, err := os.ReadFile(path)
+			require.NoError(t, err)
+			require.NoError(t, os.WriteFile(path, cut(data), 0o644))
+
+			s = open(t, path, jsonl.Options{})
+			got, err := storetest.All(ctx, s)
+			require.NoError(t, err)
+			assert.Equal(t, []store.Prediction{storetest.Pred(1)}, got, "the batch is dropped as a whole")
+
+			fi, err := os.Stat(path)
+			require.NoError(t, err)
+			assert.Equal(t, good.Size(), fi.Size(), "incomplete batch should be truncated")
+
+			// The batch can be stored again.
+			require.NoError(t, s.AppendBatch(ctx, []store.Prediction{storetest.Pred(2), storetest.Pred(3)}))
+			require.NoError(t, s.(io.Closer).Close())
+			got, err = storetest.All(ctx, open(t, path, jsonl.Options{}))
+			require.NoError(t, err)
+			assert.Equal(t, []store.Prediction{storetest.Pred(1), storetest.Pred(2), storetest.Pred(3)}, got)
+		})
+	}
+}
@@ -0,0 +1,63 @@
+package store
+
+import (
+	"errors"
+	"time"
+
+	"github.com/google/uuid"
+)
+
+// Kind tells model outputs apart from observed pod counts.
+type Kind string
+
+const (
+	// KindPredicted marks pods recommended by the model.
+	KindPredicted Kind = "predicted"
+	// KindActual marks pods observed in the training data.
+	KindActual Kind = "actual"
+)
+
+// Sources of stored predictions.
+const (
+	// SourceAPI marks predictions requested through the HTTP API.
+	SourceAPI = "api"
+	// SourceSheet marks records seeded from the training data rows.
+	SourceSheet = "sheet"
+	// SourceForecast marks per-day predictions made by GET /forecast.
+	SourceForecast = "forecast"
+)
+
+// ErrNoSource is returned by Upsert for a prediction without a Source.
+var ErrNoSource = errors.New("upsert requires a source")
+
+// Key is the natural key of an upserted prediction: at most one record
+// exists per source, kind and UTC calendar day.
+type Key struct {
+	Source string
+	Kind   Kind
+	Date   time.Time // truncated to the UTC day
+}
+
+// KeyOf returns the natural key of p.
+func KeyOf(p Prediction) Key {
+	return Key{Source: p.Source, Kind: p.Kind, Date: p.Timestamp.UTC().Truncate(24 * time.Hour)}
+}
+
+// keyNamespace scopes the IDs derived by Key.ID.
+var keyNamespace = uuid.NewSHA1(uuid.NameSpaceOID, []byte("podpredict/store.Key"))
+
+// ID returns the stable prediction ID derived from k. Upsert stores
+// predictions under this ID, so it is the same across restarts and backends.
+func (k Key) ID() string {
+	name := k.Source + "|" + string(k.Kind) + "|" + k.Date.Format(time.DateOnly)
+	return uuid.NewSHA1(keyNamespace, []byte(name)).String()
+}
+
+// PrepareUpsert validates r for Upsert and sets its ID from its natural key.
+func PrepareUpsert(r Prediction) (Prediction, error) {
+	if r.Source == "" {
+		return Prediction{}, ErrNoSource
+	}
+	r.ID = KeyOf(r).ID()
+	return r, nil
+}
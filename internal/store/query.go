@@ -0,0 +1,197 @@
+package store
+
+import (
+	"encoding/base64"
+	"errors"
+	"fmt"
+	"sort"
+	"strconv"
+	"strings"
+	"time"
+)
+
+// ErrInvalidQuery is wrapped by errors about malformed Query values,
+// such as an undecodable cursor.
+var ErrInvalidQuery = errors.New("invalid query")
+
+// Order is the sort direction of List results. Predictions are ordered by
+// Timestamp, then by ID to break ties.
+type Order string
+
+const (
+	OrderAsc  Order = "asc"
+	OrderDesc Order = "desc"
+)
+
+// Query selects, orders and paginates the predictions returned by List.
+// The zero value lists everything in ascending order.
+type Query struct {
+	// From, if non-zero, keeps predictions with Timestamp at or after it.
+	From time.Time
+	// To, if non-zero, keeps predictions with Timestamp before it.
+	To time.Time
+
+	// MinFEPods and MaxFEPods bound FEPods inclusively; 0 means unbounded.
+	MinFEPods, MaxFEPods int
+	// MinBEPods and MaxBEPods bound BEPods inclusively; 0 means unbounded.
+	MinBEPods, MaxBEPods int
+
+	// Kind, if set, keeps predictions of that kind.
+	Kind Kind
+	// Reconciled keeps only predictions with a Reconciliation.
+	Reconciled bool
+	// ModelVersion, if set, keeps predictions whose Lineage has that version.
+	ModelVersion string
+
+	// Order is OrderAsc (default) or OrderDesc.
+	Order Order
+	// Limit caps the number of returned predictions; 0 means no limit.
+	Limit int
+	// Cursor continues a previous List from its Page.NextCursor.
+	Cursor string
+}
+
+// Page is one page of List results.
+type Page struct {
+	Items []Prediction `json:"items"`
+	// NextCursor fetches the following page when passed as Query.Cursor.
+	// It is empty on the last page.
+	NextCursor string `json:"next_cursor,omitempty"`
+}
+
+// Validate reports whether q is well-formed.
+func (q Query) Validate() error {
+	switch q.Order {
+	case "", OrderAsc, OrderDesc:
+	default:
+		return fmt.Errorf("%w: order must be %q or %q", ErrInvalidQuery, OrderAsc, OrderDesc)
+	}
+	switch q.Kind {
+	case "", KindPredicted, KindActual:
+	default:
+		return fmt.Errorf("%w: kind must be %q or %q", ErrInvalidQuery, KindPredicted, KindActual)
+	}
+	if q.Limit < 0 {
+		return fmt.Errorf("%w: limit must not be negative", ErrInvalidQuery)
+	}
+	if q.MinFEPods < 0 || q.MaxFEPods < 0 || q.MinBEPods < 0 || q.MaxBEPods < 0 {
+		return fmt.Errorf("%w: pod bounds must not be negative", ErrInvalidQuery)
+	}
+	if _, err := DecodeCursor(q.Cursor); err != nil {
+		return err
+	}
+	return nil
+}
+
+// Desc reports whether results are in descending order.
+func (q Query) Desc() bool { return q.Order == OrderDesc }
+
+// Match reports whether p passes the filters of q, ignoring the cursor.
+func (q Query) Match(p Prediction) bool {
+	switch {
+	case !q.From.IsZero() && p.Timestamp.Before(q.From):
+		return false
+	case !q.To.IsZero() && !p.Timestamp.Before(q.To):
+		return false
+	case q.MinFEPods > 0 && p.FEPods < q.MinFEPods:
+		return false
+	case q.MaxFEPods > 0 && p.FEPods > q.MaxFEPods:
+		return false
+	case q.MinBEPods > 0 && p.BEPods < q.MinBEPods:
+		return false
+	case q.MaxBEPods > 0 && p.BEPods > q.MaxBEPods:
+		return false
+	case q.Kind != "" && p.Kind != q.Kind:
+		return false
+	case q.Reconciled && p.Reconciliation == nil:
+		return false
+	case q.ModelVersion != "" && (p.Lineage == nil || p.Lineage.ModelVersion != q.ModelVersion):
+		return false
+	}
+	return true
+}
+
+// Cursor is the decoded position of the last prediction of a page.
+type Cursor struct {
+	Timestamp time.Time
+	ID        string
+}
+
+// IsZero reports whether c is the start of the results.
+func (c Cursor) IsZero() bool { return c.Timestamp.IsZero() && c.ID == "" }
+
+// EncodeCursor returns an opaque cursor positioned at p.
+func EncodeCursor(p Prediction) string {
+	raw := strconv.FormatInt(p.Timestamp.UnixNano(), 10) + "|" + p.ID
+	return base64.RawURLEncoding.EncodeToString([]byte(raw))
+}
+
+// DecodeCursor parses a cursor produced by EncodeCursor.
+// The empty string decodes to the zero Cursor.
+func DecodeCursor(s string) (Cursor, error) {
+	if s == "" {
+		return Cursor{}, nil
+	}
+	raw, err := base64.RawURLEncoding.DecodeString(s)
+	if err != nil {
+		return Cursor{}, fmt.Errorf("%w: malformed cursor", ErrInvalidQuery)
+	}
+	ts, id, ok := strings.Cut(string(raw), "|")
+	ns, err := strconv.ParseInt(ts, 10, 64)
+	if !ok || err != nil {
+		return Cursor{}, fmt.Errorf("%w: malformed cursor", ErrInvalidQuery)
+	}
+	return Cursor{Timestamp: time.Unix(0, ns).UTC(), ID: id}, nil
+}
+
+// less orders predictions by Timestamp, then ID.
+func less(a, b Prediction) bool {
+	if !a.Timestamp.Equal(b.Timestamp) {
+		return a.Timestamp.Before(b.Timestamp)
+	}
+	return a.ID < b.ID
+}
+
+// after reports whether p comes after c in the given order.
+func after(p Prediction, c Cursor, desc bool) bool {
+	at := Prediction{Timestamp: c.Timestamp, ID: c.ID}
+	if desc {
+		return less(p, at)
+	}
+	return less(at, p)
+}
+
+// Apply runs q over items in memory. It is meant for backends without a
+// query engine of their own; items is not modified.
+func Apply(items []Prediction, q Query) (Page, error) {
+	if err := q.Validate(); err != nil {
+		return Page{}, err
+	}
+	cur, _ := DecodeCursor(q.Cursor)
+	desc := q.Desc()
+
+	out := make([]Prediction, 0)
+	for _, p := range items {
+		if q.Match(p) && (cur.IsZero() || after(p, cur, desc)) {
+			out = append(out, p)
+		}
+	}
+	sort.SliceStable(out, func(i, j int) bool {
+		if desc {
+			return less(out[j], out[i])
+		}
+		return less(out[i], out[j])
+	})
+	return NewPage(out, q.Limit), nil
+}
+
+// NewPage trims items, already filtered and ordered, to limit and sets
+// NextCursor if any were left out. Backends fetch limit+1 items so that
+// NewPage can tell whether another page exists.
+func NewPage(items []Prediction, limit int) Page {
+	if limit <= 0 || len(items) <= limit {
+		return Page{Items: items}
+	}
+	items = items[:limit]
+	return Page{Items: items, NextCursor: EncodeCursor(items[limit-1])}
+}
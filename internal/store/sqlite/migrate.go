@@ -0,0 +1,74 @@
+package sqlite
+
+import (
+	"context"
+	"database/sql"
+	"fmt"
+)
+
+// migrations are applied in order, each in its own transaction. The number
+// of applied migrations is kept in PRAGMA user_version. Never edit or
+// reorder an existing entry; append a new one instead.
+var migrations = []string{
+	// 1: predictions table with an index for time-range queries.
+	`CREATE TABLE predictions (
+		seq            INTEGER PRIMARY KEY AUTOINCREMENT,
+		id             TEXT    NOT NULL UNIQUE,
+		timestamp      INTEGER NOT NULL, -- Unix nanoseconds, UTC
+		gmv            REAL    NOT NULL,
+		users          REAL    NOT NULL,
+		marketing_cost REAL    NOT NULL,
+		fe_pods        INTEGER NOT NULL,
+		be_pods        INTEGER NOT NULL,
+		interval       TEXT              -- JSON model.Interval, NULL when absent
+	);
+	CREATE INDEX predictions_timestamp_idx ON predictions (timestamp);`,
+	// 2: List orders and paginates by (timestamp, id).
+	`DROP INDEX predictions_timestamp_idx;
+	CREATE INDEX predictions_timestamp_id_idx ON predictions (timestamp, id);`,
+	// 3: provenance of upserted and API predictions.
+	`ALTER TABLE predictions ADD COLUMN source TEXT NOT NULL DEFAULT '';
+	ALTER TABLE predictions ADD COLUMN kind TEXT NOT NULL DEFAULT '';`,
+	// 4: reconciliation of predictions against later actuals.
+	`ALTER TABLE predictions ADD COLUMN reconciliation TEXT; -- JSON store.Reconciliation, NULL until reconciled`,
+	// 5: model lineage, NULL for actuals.
+	`ALTER TABLE predictions ADD COLUMN model_version TEXT;
+	ALTER TABLE predictions ADD COLUMN model_type TEXT;
+	ALTER TABLE predictions ADD COLUMN data_fingerprint TEXT;
+	ALTER TABLE predictions ADD COLUMN trained_at INTEGER; -- Unix nanoseconds, UTC
+	CREATE INDEX predictions_model_version_idx ON predictions (model_version);`,
+}
+
+// migrate brings the schema of db up to date.
+func migrate(ctx context.Context, db *sql.DB) error {
+	var version int
+	if err := db.QueryRowContext(ctx, `PRAGMA user_version`).Scan(&version); err != nil {
+		return err
+	}
+	if version > len(migrations) {
+		return fmt.Errorf("database schema version %d is newer than supported version %d", version, len(migrations))
+	}
+	for i := version; i < len(migrations); i++ {
+		if err := apply(ctx, db, i+1, migrations[i]); err != nil {
+			return fmt.Errorf("migration %d: %w", i+1, err)
+		}
+	}
+	return nil
+}
+
+func apply(ctx context.Context, db *sql.DB, version int, stmt string) error {
+	tx, err := db.BeginTx(ctx, nil)
+	if err != nil {
+		return err
+	}
+	defer func() { _ = tx.Rollback() }()
+
+	if _, err := tx.ExecContext(ctx, stmt); err != nil {
+		return err
+	}
+	// PRAGMA does not accept bound parameters.
+	if _, err := tx.ExecContext(ctx, fmt.Sprintf(`PRAGMA user_version = %d`, version)); err != nil {
+		return err
+	}
+	return tx.Commit()
+}
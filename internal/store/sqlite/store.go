@@ -0,0 +1,356 @@
+// Package sqlite implements store.Store on top of an SQLite database using
+// the pure-Go modernc.org/sqlite driver, so builds stay CGO-free.
+package sqlite
+
+import (
+	"context"
+	"database/sql"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"net/url"
+	"strings"
+	"time"
+
+	driver "modernc.org/sqlite" // registers the "sqlite" driver
+	sqlite3 "modernc.org/sqlite/lib"
+
+	"github.com/thisiscetin/podpredict/internal/store"
+)
+
+// DefaultBusyTimeout is how long a statement waits for a locked database.
+const DefaultBusyTimeout = 5 * time.Second
+
+// impl is an SQLite-backed implementation of the store.Store interface.
+// It is safe for concurrent use.
+type impl struct {
+	db *sql.DB
+}
+
+// NewStore opens (creating if necessary) the SQLite database at path and
+// applies any pending schema migrations. ctx bounds the migrations.
+// The returned store also implements io.Closer; call Close on shutdown.
+func NewStore(ctx context.Context, path string) (store.Store, error) {
+	q := url.Values{}
+	q.Add("_pragma", fmt.Sprintf("busy_timeout(%d)", DefaultBusyTimeout.Milliseconds()))
+	q.Add("_pragma", "journal_mode(WAL)")
+	q.Add("_pragma", "synchronous(NORMAL)")
+	db, err := sql.Open("sqlite", "file:"+path+"?"+q.Encode())
+	if err != nil {
+		return nil, err
+	}
+	// SQLite allows a single writer; one connection avoids SQLITE_BUSY
+	// between our own goroutines and keeps :memory: databases shared.
+	db.SetMaxOpenConns(1)
+
+	if err := migrate(ctx, db); err != nil {
+		_ = db.Close()
+		return nil, fmt.Errorf("migrating %s: %w", path, err)
+	}
+	return &impl{db: db}, nil
+}
+
+// Append inserts the prediction. It fails with store.ErrExists if a
+// prediction with the same ID already exists.
+func (s *impl) Append(ctx context.Context, r store.Prediction) error {
+	vals, err := args(r)
+	if err != nil {
+		return err
+	}
+	_, err = s.db.ExecContext(ctx, `
+		INSERT INTO predictions (`+columns+`)
+		VALUES (`+placeholders+`)`,
+		vals...,
+	)
+	return mapErr(err)
+}
+
+// AppendBatch inserts rs in a single transaction. It fails with
+// store.ErrExists, storing nothing, if any ID is already stored or
+// repeated within rs.
+func (s *impl) AppendBatch(ctx context.Context, rs []store.Prediction) error {
+	tx, err := s.db.BeginTx(ctx, nil)
+	if err != nil {
+		return err
+	}
+	defer func() { _ = tx.Rollback() }()
+
+	stmt, err := tx.PrepareContext(ctx, `INSERT INTO predictions (`+columns+`) VALUES (`+placeholders+`)`)
+	if err != nil {
+		return err
+	}
+	defer stmt.Close()
+
+	for _, r := range rs {
+		vals, err := args(r)
+		if err != nil {
+			return err
+		}
+		if _, err := stmt.ExecContext(ctx, vals...); err != nil {
+			return mapErr(err)
+		}
+	}
+	return tx.Commit()
+}
+
+// mapErr translates unique constraint violations into store.ErrExists.
+func mapErr(err error) error {
+	var se *driver.Error
+	if errors.As(err, &se) && se.Code() == sqlite3.SQLITE_CONSTRAINT_UNIQUE {
+		return store.ErrExists
+	}
+	return err
+}
+
+// columns lists the predictions columns in the order scan expects.
+const columns = `id, timestamp, gmv, users, marketing_cost, fe_pods, be_pods, interval, source, kind, reconciliation,
+	model_version, model_type, data_fingerprint, trained_at`
+
+// placeholders has one bind parameter per entry of columns.
+const placeholders = `?, ?, ?, ?, ?, ?, ?, ?, ?, ?, ?, ?, ?, ?, ?`
+
+// args returns the column values of r in the order of columns.
+func args(r store.Prediction) ([]any, error) {
+	interval, err := encodeJSON(r.Interval)
+	if err != nil {
+		return nil, err
+	}
+	rec, err := encodeJSON(r.Reconciliation)
+	if err != nil {
+		return nil, err
+	}
+	var (
+		version, typ, fingerprint sql.NullString
+		trainedAt                 sql.NullInt64
+	)
+	if l := r.Lineage; l != nil {
+		version = sql.NullString{String: l.ModelVersion, Valid: true}
+		typ = sql.NullString{String: l.ModelType, Valid: true}
+		fingerprint = sql.NullString{String: l.DataFingerprint, Valid: true}
+		trainedAt = sql.NullInt64{Int64: l.TrainedAt.UTC().UnixNano(), Valid: !l.TrainedAt.IsZero()}
+	}
+	return []any{
+		r.ID, r.Timestamp.UTC().UnixNano(), r.Input.GMV, r.Input.Users, r.Input.MarketingCost,
+		r.FEPods, r.BEPods, interval, r.Source, string(r.Kind), rec,
+		version, typ, fingerprint, trainedAt,
+	}, nil
+}
+
+// Upsert inserts r under the ID derived from its natural key, or updates
+// the row already stored under that ID.
+func (s *impl) Upsert(ctx context.Context, r store.Prediction) error {
+	r, err := store.PrepareUpsert(r)
+	if err != nil {
+		return err
+	}
+	vals, err := args(r)
+	if err != nil {
+		return err
+	}
+	_, err = s.db.ExecContext(ctx, `
+		INSERT INTO predictions (`+columns+`)
+		VALUES (`+placeholders+`)
+		ON CONFLICT (id) DO UPDATE SET
+			timestamp = excluded.timestamp,
+			gmv = excluded.gmv,
+			users = excluded.users,
+			marketing_cost = excluded.marketing_cost,
+			fe_pods = excluded.fe_pods,
+			be_pods = excluded.be_pods,
+			interval = excluded.interval,
+			source = excluded.source,
+			kind = excluded.kind,
+			reconciliation = excluded.reconciliation,
+			model_version = excluded.model_version,
+			model_type = excluded.model_type,
+			data_fingerprint = excluded.data_fingerprint,
+			trained_at = excluded.trained_at`,
+		vals...,
+	)
+	return err
+}
+
+// Get returns the prediction with the given ID, or store.ErrNotFound.
+func (s *impl) Get(ctx context.Context, id string) (store.Prediction, error) {
+	row := s.db.QueryRowContext(ctx, `SELECT `+columns+` FROM predictions WHERE id = ?`, id)
+	p, err := scan(row)
+	if errors.Is(err, sql.ErrNoRows) {
+		return store.Prediction{}, store.ErrNotFound
+	}
+	return p, err
+}
+
+// Delete removes the prediction with the given ID, or returns store.ErrNotFound.
+func (s *impl) Delete(ctx context.Context, id string) error {
+	res, err := s.db.ExecContext(ctx, `DELETE FROM predictions WHERE id = ?`, id)
+	if err != nil {
+		return err
+	}
+	n, err := res.RowsAffected()
+	if err != nil {
+		return err
+	}
+	if n == 0 {
+		return store.ErrNotFound
+	}
+	return nil
+}
+
+// List returns the predictions selected by q. Filters, ordering, the
+// cursor and the limit are all evaluated by SQLite.
+func (s *impl) List(ctx context.Context, q store.Query) (store.Page, error) {
+	if err := q.Validate(); err != nil {
+		return store.Page{}, err
+	}
+	where, args := filter(q)
+	dir := "ASC"
+	if q.Desc() {
+		dir = "DESC"
+	}
+	limit := -1 // no limit
+	if q.Limit > 0 {
+		limit = q.Limit + 1 // one extra row tells whether another page exists
+	}
+
+	rows, err := s.db.QueryContext(ctx, `
+		SELECT `+columns+`
+		FROM predictions`+where+`
+		ORDER BY timestamp `+dir+`, id `+dir+`
+		LIMIT ?`, append(args, limit)...)
+	if err != nil {
+		return store.Page{}, err
+	}
+	defer rows.Close()
+
+	out := make([]store.Prediction, 0)
+	for rows.Next() {
+		p, err := scan(rows)
+		if err != nil {
+			return store.Page{}, err
+		}
+		out = append(out, p)
+	}
+	if err := rows.Err(); err != nil {
+		return store.Page{}, err
+	}
+	return store.NewPage(out, q.Limit), nil
+}
+
+// scan reads one row of columns into a prediction.
+func scan(row interface{ Scan(...any) error }) (store.Prediction, error) {
+	var (
+		p        store.Prediction
+		ts       int64
+		interval sql.NullString
+		kind     string
+		rec      sql.NullString
+
+		version, typ, fingerprint sql.NullString
+		trainedAt                 sql.NullInt64
+	)
+	if err := row.Scan(&p.ID, &ts, &p.Input.GMV, &p.Input.Users, &p.Input.MarketingCost,
+		&p.FEPods, &p.BEPods, &interval, &p.Source, &kind, &rec,
+		&version, &typ, &fingerprint, &trainedAt); err != nil {
+		return store.Prediction{}, err
+	}
+	if version.Valid {
+		p.Lineage = &store.Lineage{
+			ModelVersion:    version.String,
+			ModelType:       typ.String,
+			DataFingerprint: fingerprint.String,
+		}
+		if trainedAt.Valid {
+			p.Lineage.TrainedAt = time.Unix(0, trainedAt.Int64).UTC()
+		}
+	}
+	p.Timestamp = time.Unix(0, ts).UTC()
+	p.Kind = store.Kind(kind)
+	if err := decodeJSON(interval, &p.Interval); err != nil {
+		return store.Prediction{}, fmt.Errorf("prediction %s: %w", p.ID, err)
+	}
+	if err := decodeJSON(rec, &p.Reconciliation); err != nil {
+		return store.Prediction{}, fmt.Errorf("prediction %s: %w", p.ID, err)
+	}
+	return p, nil
+}
+
+// filter translates the filters and cursor of q into a WHERE clause.
+func filter(q store.Query) (string, []any) {
+	var (
+		conds []string
+		args  []any
+	)
+	add := func(cond string, vals ...any) {
+		conds = append(conds, cond)
+		args = append(args, vals...)
+	}
+	if !q.From.IsZero() {
+		add("timestamp >= ?", q.From.UnixNano())
+	}
+	if !q.To.IsZero() {
+		add("timestamp < ?", q.To.UnixNano())
+	}
+	for _, b := range []struct {
+		cond string
+		val  int
+	}{
+		{"fe_pods >= ?", q.MinFEPods},
+		{"fe_pods <= ?", q.MaxFEPods},
+		{"be_pods >= ?", q.MinBEPods},
+		{"be_pods <= ?", q.MaxBEPods},
+	} {
+		if b.val > 0 {
+			add(b.cond, b.val)
+		}
+	}
+	if q.Kind != "" {
+		add("kind = ?", string(q.Kind))
+	}
+	if q.Reconciled {
+		add("reconciliation IS NOT NULL")
+	}
+	if q.ModelVersion != "" {
+		add("model_version = ?", q.ModelVersion)
+	}
+	if cur, _ := store.DecodeCursor(q.Cursor); !cur.IsZero() {
+		op := ">"
+		if q.Desc() {
+			op = "<"
+		}
+		add("(timestamp, id) "+op+" (?, ?)", cur.Timestamp.UnixNano(), cur.ID)
+	}
+	if len(conds) == 0 {
+		return "", nil
+	}
+	return " WHERE " + strings.Join(conds, " AND "), args
+}
+
+// Close closes the underlying database.
+func (s *impl) Close() error {
+	return s.db.Close()
+}
+
+// encodeJSON stores an optional value as JSON, or NULL when v is nil.
+func encodeJSON[T any](v *T) (sql.NullString, error) {
+	if v == nil {
+		return sql.NullString{}, nil
+	}
+	b, err := json.Marshal(v)
+	if err != nil {
+		return sql.NullString{}, err
+	}
+	return sql.NullString{String: string(b), Valid: true}, nil
+}
+
+// decodeJSON sets *dst from a column written by encodeJSON; NULL leaves it nil.
+func decodeJSON[T any](s sql.NullString, dst **T) error {
+	if !s.Valid {
+		return nil
+	}
+	v := new(T)
+	if err := json.Unmarshal([]byte(s.String), v); err != nil {
+		return err
+	}
+	*dst = v
+	return nil
+}
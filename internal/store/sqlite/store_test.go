@@ -0,0 +1,55 @@
+package sqlite_test
+
+import (
+	"context"
+	"io"
+	"path/filepath"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"github.com/thisiscetin/podpredict/internal/store"
+	"github.com/thisiscetin/podpredict/internal/store/sqlite"
+	"github.com/thisiscetin/podpredict/internal/store/storetest"
+)
+
+func newStore(t *testing.T, path string) store.Store {
+	t.Helper()
+	s, err := sqlite.NewStore(context.Background(), path)
+	require.NoError(t, err)
+	t.Cleanup(func() { _ = s.(io.Closer).Close() })
+	return s
+}
+
+func TestConformance(t *testing.T) {
+	storetest.Run(t, func(t *testing.T) store.Store {
+		return newStore(t, filepath.Join(t.TempDir(), "predictions.db"))
+	})
+}
+
+func TestPersistsAcrossReopen(t *testing.T) {
+	path := filepath.Join(t.TempDir(), "predictions.db")
+	ctx := context.Background()
+
+	s, err := sqlite.NewStore(ctx, path)
+	require.NoError(t, err)
+	require.NoError(t, s.Append(ctx, storetest.Pred(1)))
+	require.NoError(t, s.Append(ctx, storetest.Pred(2)))
+	require.NoError(t, s.(io.Closer).Close())
+
+	// Reopening must not re-run migrations or lose data.
+	got, err := storetest.All(ctx, newStore(t, path))
+	require.NoError(t, err)
+	assert.Equal(t, []store.Prediction{storetest.Pred(1), storetest.Pred(2)}, got)
+}
+
+func TestCanceledContext(t *testing.T) {
+	s := newStore(t, filepath.Join(t.TempDir(), "predictions.db"))
+	ctx, cancel := context.WithCancel(context.Background())
+	cancel()
+
+	assert.ErrorIs(t, s.Append(ctx, storetest.Pred(1)), context.Canceled)
+	_, err := storetest.All(ctx, s)
+	assert.ErrorIs(t, err, context.Canceled)
+}
@@ -2,21 +2,46 @@ package store
 
 import (
 	"context"
+	"errors"
+	"math"
 	"time"
 
 	"github.com/thisiscetin/podpredict/internal/model"
 )
 
+var (
+	// ErrNotFound is returned when no prediction has the requested ID.
+	ErrNotFound = errors.New("prediction not found")
+	// ErrExists is returned by Append when the ID is already stored.
+	ErrExists = errors.New("prediction already exists")
+)
+
+// Stores order and page predictions by their Timestamp in Unix
+// nanoseconds, which only represent times from MinTimestamp to MaxTimestamp
+// (years 1677 to 2262).
+var (
+	MinTimestamp = time.Unix(0, math.MinInt64).UTC()
+	MaxTimestamp = time.Unix(0, math.MaxInt64).UTC()
+)
+
+// ValidTimestamp reports whether t is within MinTimestamp and MaxTimestamp.
+func ValidTimestamp(t time.Time) bool {
+	return !t.Before(MinTimestamp) && !t.After(MaxTimestamp)
+}
+
 // Prediction represents a single model output and its metadata.
-// Each prediction contains a unique ID, a UTC timestamp indicating when
-// it was generated, the input feature values used to compute it, and
+// Each prediction contains a unique ID, a UTC timestamp of the day it is
+// for, the input feature values used to compute it, and
 // the resulting predicted number of front-end (FE) and back-end (BE) pods.
 // Prediction values are immutable after creation and can be safely copied.
 type Prediction struct {
 	// ID uniquely identifies this prediction. It is typically a UUID string.
 	ID string `json:"id"`
 
-	// Timestamp records when the prediction was created, in UTC.
+	// Timestamp is the day the pods are for, in UTC: the target date of
+	// sheet rows (SourceSheet), forecasts (SourceForecast) and dated batch
+	// items, or the time of the request for other API predictions. Its UTC
+	// date is part of the natural key used by Upsert and reconciliation.
 	Timestamp time.Time `json:"timestamp"`
 
 	// Input contains the model feature values (e.g., GMV, Users, MarketingCost)
@@ -28,6 +53,52 @@ type Prediction struct {
 
 	// BEPods is the predicted number of back-end pods required.
 	BEPods int `json:"be_pods"`
+
+	// Source records where the prediction came from, e.g. SourceAPI or SourceSheet.
+	Source string `json:"source,omitempty"`
+
+	// Kind is KindPredicted for model outputs and KindActual for observed pods.
+	Kind Kind `json:"kind,omitempty"`
+
+	// Lineage identifies the model that produced the prediction.
+	// It is nil for actuals.
+	Lineage *Lineage `json:"lineage,omitempty"`
+
+	// Reconciliation compares a predicted record with the actual pods later
+	// observed for its day. It is nil until the actuals are known.
+	Reconciliation *Reconciliation `json:"reconciliation,omitempty"`
+
+	// Interval holds the FE/BE prediction intervals when the caller asked for
+	// a confidence level, nil otherwise. Interval.Bound records which value
+	// FEPods and BEPods were taken from.
+	Interval *model.Interval `json:"interval,omitempty"`
+}
+
+// Lineage traces a prediction back to the model and data that produced it.
+type Lineage struct {
+	// ModelVersion changes on every successful training.
+	ModelVersion string `json:"model_version"`
+	// ModelType names the model implementation, e.g. "linreg".
+	ModelType string `json:"model_type,omitempty"`
+	// DataFingerprint is metrics.Fingerprint of the training data.
+	DataFingerprint string `json:"data_fingerprint"`
+	// TrainedAt is when the model finished training, in UTC.
+	TrainedAt time.Time `json:"trained_at"`
+}
+
+// Reconciliation is the error of a prediction once actual pods are known.
+type Reconciliation struct {
+	// ActualFEPods and ActualBEPods are the observed pods for the day.
+	ActualFEPods int `json:"actual_fe_pods"`
+	ActualBEPods int `json:"actual_be_pods"`
+
+	// FEError and BEError are predicted minus actual pods; negative values
+	// mean the day was under-provisioned.
+	FEError int `json:"fe_error"`
+	BEError int `json:"be_error"`
+
+	// ReconciledAt records when the actuals were matched, in UTC.
+	ReconciledAt time.Time `json:"reconciled_at"`
 }
 
 // Store defines the interface for persisting and retrieving predictions.
@@ -39,12 +110,33 @@ type Store interface {
 	// Implementations should ensure the operation is atomic and safe
 	// for concurrent writers. The provided context can be used to
 	// cancel the operation early if supported by the backend.
+	// It fails with ErrExists if a prediction with the same ID is stored.
 	Append(ctx context.Context, r Prediction) error
 
-	// List returns all stored predictions.
+	// AppendBatch adds rs in one call. It stores all of them or none:
+	// it fails with ErrExists if any ID is already stored or repeated
+	// within rs.
+	AppendBatch(ctx context.Context, rs []Prediction) error
+
+	// Upsert inserts r or replaces the prediction with the same natural
+	// key (see Key): its Source, Kind and the UTC date of its Timestamp.
+	// r.ID is ignored; the stored ID is KeyOf(r).ID(). It fails with
+	// ErrNoSource if r.Source is empty.
+	Upsert(ctx context.Context, r Prediction) error
+
+	// Get returns the prediction with the given ID, or ErrNotFound.
+	Get(ctx context.Context, id string) (Prediction, error)
+
+	// Delete removes the prediction with the given ID, or returns ErrNotFound.
+	Delete(ctx context.Context, id string) error
+
+	// List returns the predictions selected by q, ordered by Timestamp
+	// and then ID, one page at a time. Backends should push the filters
+	// down rather than load everything; in-memory backends can use Apply.
+	// Malformed queries fail with an error wrapping ErrInvalidQuery.
 	// Implementations should return a defensive copy so that callers
 	// can modify the result without affecting internal state. The
 	// provided context can be used to cancel the operation early
 	// if supported by the backend.
-	List(ctx context.Context) ([]Prediction, error)
+	List(ctx context.Context, q Query) (Page, error)
 }
@@ -0,0 +1,483 @@
+// Package storetest provides a conformance suite that every store.Store
+// implementation is expected to pass.
+package storetest
+
+import (
+	"context"
+	"strconv"
+	"sync"
+	"testing"
+	"time"
+
+	"github.com/google/uuid"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"github.com/thisiscetin/podpredict/internal/model"
+	"github.com/thisiscetin/podpredict/internal/store"
+)
+
+// Factory returns a new, empty store for a single test. Implementations
+// should register any cleanup with t.Cleanup.
+type Factory func(t *testing.T) store.Store
+
+// Run runs the conformance suite against stores built by newStore.
+func Run(t *testing.T, newStore Factory) {
+	tests := []struct {
+		name string
+		fn   func(*testing.T, Factory)
+	}{
+		{"NewStoreIsEmpty", testNewStoreIsEmpty},
+		{"AppendAndListOrderAndCopyIsolation", testAppendAndListOrderAndCopyIsolation},
+		{"RoundTripsAllFields", testRoundTripsAllFields},
+		{"ConcurrentAppendIsRaceSafeAndCounts", testConcurrentAppendIsRaceSafeAndCounts},
+		{"ListReturnsIndependentSlicesEachCall", testListReturnsIndependentSlicesEachCall},
+		{"ListOrdersByTimestampThenID", testListOrdersByTimestampThenID},
+		{"ListFilters", testListFilters},
+		{"ListFiltersKindAndReconciled", testListFiltersKindAndReconciled},
+		{"ListFiltersModelVersion", testListFiltersModelVersion},
+		{"ListPaginates", testListPaginates},
+		{"ListRejectsInvalidQuery", testListRejectsInvalidQuery},
+		{"AppendDuplicateIDFails", testAppendDuplicateIDFails},
+		{"GetAndDelete", testGetAndDelete},
+		{"GetAndDeleteMissing", testGetAndDeleteMissing},
+		{"AppendBatch", testAppendBatch},
+		{"AppendBatchIsAllOrNothing", testAppendBatchIsAllOrNothing},
+		{"UpsertByNaturalKey", testUpsertByNaturalKey},
+		{"UpsertRequiresSource", testUpsertRequiresSource},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) { tt.fn(t, newStore) })
+	}
+}
+
+// All lists every prediction in s with the zero Query.
+func All(ctx context.Context, s store.Store) ([]store.Prediction, error) {
+	page, err := s.List(ctx, store.Query{})
+	return page.Items, err
+}
+
+// Pred creates a deterministic, distinguishable prediction for tests.
+func Pred(i int) store.Prediction {
+	return store.Prediction{
+		// Derive a stable UUID from the index 'i' so repeated calls with the same
+		// argument produce identical IDs (critical for equality assertions).
+		ID:        uuid.NewSHA1(uuid.NameSpaceOID, []byte(strconv.Itoa(i))).String(),
+		Timestamp: time.Unix(1_700_000_000+int64(i), 0).UTC(),
+		Input: model.Features{
+			GMV:           float64(100*i + 1),
+			Users:         float64(10*i + 2),
+			MarketingCost: float64(i%3 + 3),
+		},
+		FEPods: i + 1,
+		BEPods: (i + 1) * 2,
+	}
+}
+
+func testNewStoreIsEmpty(t *testing.T, newStore Factory) {
+	s := newStore(t)
+
+	got, err := All(context.Background(), s)
+	require.NoError(t, err)
+	assert.Len(t, got, 0, "new store should list zero predictions")
+}
+
+func testAppendAndListOrderAndCopyIsolation(t *testing.T, newStore Factory) {
+	s := newStore(t)
+
+	// Append three distinct predictions (check insertion order is preserved)
+	p1 := Pred(0)
+	p2 := Pred(1)
+	p3 := Pred(2)
+
+	require.NoError(t, s.Append(context.Background(), p1))
+	require.NoError(t, s.Append(context.Background(), p2))
+	require.NoError(t, s.Append(context.Background(), p3))
+
+	// First list call
+	got, err := All(context.Background(), s)
+	require.NoError(t, err)
+	require.Len(t, got, 3)
+
+	assert.Equal(t, p1, got[0])
+	assert.Equal(t, p2, got[1])
+	assert.Equal(t, p3, got[2])
+
+	// Mutate the returned slice (should NOT affect internal state)
+	got[0] = Pred(999)
+
+	// List again and confirm original values are intact
+	got2, err := All(context.Background(), s)
+	require.NoError(t, err)
+	require.Len(t, got2, 3)
+
+	assert.Equal(t, p1, got2[0], "internal slice must be protected from external mutation")
+	assert.Equal(t, p2, got2[1])
+	assert.Equal(t, p3, got2[2])
+}
+
+func testRoundTripsAllFields(t *testing.T, newStore Factory) {
+	s := newStore(t)
+	ctx := context.Background()
+
+	p := Pred(7)
+	p.Timestamp = time.Date(2025, 11, 28, 9, 30, 15, 123456789, time.UTC)
+	p.Input.GMV = 1234.5678
+	p.Source = store.SourceAPI
+	p.Kind = store.KindPredicted
+	p.Lineage = &store.Lineage{
+		ModelVersion:    "v1",
+		ModelType:       "linreg",
+		DataFingerprint: "sha256:abc",
+		TrainedAt:       time.Date(2025, 11, 27, 23, 0, 0, 0, time.UTC),
+	}
+	p.Reconciliation = &store.Reconciliation{
+		ActualFEPods: 7,
+		ActualBEPods: 2,
+		FEError:      1,
+		BEError:      -1,
+		ReconciledAt: time.Date(2025, 11, 29, 6, 0, 0, 0, time.UTC),
+	}
+	p.Interval = &model.Interval{
+		Confidence: 0.9,
+		Bound:      model.BoundUpper,
+		FE:         model.Bounds{Point: 4.2, Lower: 3.1, Upper: 5.7},
+		BE:         model.Bounds{Point: 2.5, Lower: 1.9, Upper: 3.3},
+	}
+	require.NoError(t, s.Append(ctx, p))
+
+	got, err := All(ctx, s)
+	require.NoError(t, err)
+	require.Len(t, got, 1)
+	assert.Equal(t, p, got[0])
+}
+
+func testConcurrentAppendIsRaceSafeAndCounts(t *testing.T, newStore Factory) {
+	s := newStore(t)
+	ctx := context.Background()
+
+	const N = 200
+	var wg sync.WaitGroup
+	wg.Add(N)
+
+	for i := 0; i < N; i++ {
+		go func() {
+			defer wg.Done()
+			assert.NoError(t, s.Append(ctx, Pred(i)))
+		}()
+	}
+	wg.Wait()
+
+	got, err := All(ctx, s)
+	require.NoError(t, err)
+	assert.Len(t, got, N, "store should contain all appended predictions")
+
+	// Order is not guaranteed under concurrency; just ensure a known element is present.
+	assert.Contains(t, got, Pred(42), "expected to find a previously appended record")
+}
+
+func testListReturnsIndependentSlicesEachCall(t *testing.T, newStore Factory) {
+	s := newStore(t)
+	ctx := context.Background()
+
+	require.NoError(t, s.Append(ctx, Pred(0)))
+	require.NoError(t, s.Append(ctx, Pred(1)))
+
+	a, err := All(ctx, s)
+	require.NoError(t, err)
+	b, err := All(ctx, s)
+	require.NoError(t, err)
+
+	require.Len(t, a, 2)
+	require.Len(t, b, 2)
+
+	// mutate one result; the other should remain unaffected
+	a[1] = Pred(777)
+
+	c, err := All(ctx, s)
+	require.NoError(t, err)
+	require.Len(t, c, 2)
+
+	assert.Equal(t, Pred(1), c[1], "fresh List() result must not be influenced by prior callers' mutations")
+}
+
+func testListOrdersByTimestampThenID(t *testing.T, newStore Factory) {
+	s := newStore(t)
+	ctx := context.Background()
+
+	// Appended out of order; p1 and p2 share a timestamp.
+	p1, p2, p3 := Pred(1), Pred(2), Pred(3)
+	p2.Timestamp = p1.Timestamp
+	if p2.ID < p1.ID {
+		p1, p2 = p2, p1
+	}
+	for _, p := range []store.Prediction{p3, p2, p1} {
+		require.NoError(t, s.Append(ctx, p))
+	}
+
+	asc, err := s.List(ctx, store.Query{})
+	require.NoError(t, err)
+	assert.Equal(t, []store.Prediction{p1, p2, p3}, asc.Items)
+
+	desc, err := s.List(ctx, store.Query{Order: store.OrderDesc})
+	require.NoError(t, err)
+	assert.Equal(t, []store.Prediction{p3, p2, p1}, desc.Items)
+}
+
+func testListFilters(t *testing.T, newStore Factory) {
+	s := newStore(t)
+	ctx := context.Background()
+	for i := 0; i < 10; i++ {
+		require.NoError(t, s.Append(ctx, Pred(i))) // FEPods i+1, BEPods 2(i+1)
+	}
+
+	tests := []struct {
+		name string
+		q    store.Query
+		want []int
+	}{
+		{"from inclusive", store.Query{From: Pred(7).Timestamp}, []int{7, 8, 9}},
+		{"to exclusive", store.Query{To: Pred(2).Timestamp}, []int{0, 1}},
+		{"fe range", store.Query{MinFEPods: 3, MaxFEPods: 4}, []int{2, 3}},
+		{"be range", store.Query{MinBEPods: 16}, []int{7, 8, 9}},
+		{"combined", store.Query{From: Pred(1).Timestamp, MaxBEPods: 6, Order: store.OrderDesc}, []int{2, 1}},
+		{"no match", store.Query{MinFEPods: 100}, nil},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			page, err := s.List(ctx, tt.q)
+			require.NoError(t, err)
+			want := make([]store.Prediction, 0, len(tt.want))
+			for _, i := range tt.want {
+				want = append(want, Pred(i))
+			}
+			assert.Equal(t, want, page.Items)
+			assert.Empty(t, page.NextCursor)
+		})
+	}
+}
+
+func testListFiltersKindAndReconciled(t *testing.T, newStore Factory) {
+	s := newStore(t)
+	ctx := context.Background()
+
+	actual, predicted, reconciled := Pred(0), Pred(1), Pred(2)
+	actual.Kind = store.KindActual
+	predicted.Kind = store.KindPredicted
+	reconciled.Kind = store.KindPredicted
+	reconciled.Reconciliation = &store.Reconciliation{ActualFEPods: 3, ActualBEPods: 6}
+	for _, p := range []store.Prediction{actual, predicted, reconciled} {
+		require.NoError(t, s.Append(ctx, p))
+	}
+
+	page, err := s.List(ctx, store.Query{Kind: store.KindPredicted})
+	require.NoError(t, err)
+	assert.Equal(t, []store.Prediction{predicted, reconciled}, page.Items)
+
+	page, err = s.List(ctx, store.Query{Kind: store.KindActual})
+	require.NoError(t, err)
+	assert.Equal(t, []store.Prediction{actual}, page.Items)
+
+	page, err = s.List(ctx, store.Query{Reconciled: true})
+	require.NoError(t, err)
+	assert.Equal(t, []store.Prediction{reconciled}, page.Items)
+}
+
+func testListFiltersModelVersion(t *testing.T, newStore Factory) {
+	s := newStore(t)
+	ctx := context.Background()
+
+	v1, v2, none := Pred(0), Pred(1), Pred(2)
+	v1.Lineage = &store.Lineage{ModelVersion: "v1"}
+	v2.Lineage = &store.Lineage{ModelVersion: "v2"}
+	for _, p := range []store.Prediction{v1, v2, none} {
+		require.NoError(t, s.Append(ctx, p))
+	}
+
+	page, err := s.List(ctx, store.Query{ModelVersion: "v2"})
+	require.NoError(t, err)
+	assert.Equal(t, []store.Prediction{v2}, page.Items)
+}
+
+func testListPaginates(t *testing.T, newStore Factory) {
+	s := newStore(t)
+	ctx := context.Background()
+	const N = 7
+	for i := 0; i < N; i++ {
+		require.NoError(t, s.Append(ctx, Pred(i)))
+	}
+
+	for _, order := range []store.Order{store.OrderAsc, store.OrderDesc} {
+		t.Run(string(order), func(t *testing.T) {
+			var got []store.Prediction
+			q := store.Query{Limit: 3, Order: order}
+			pages := 0
+			for {
+				page, err := s.List(ctx, q)
+				require.NoError(t, err)
+				assert.LessOrEqual(t, len(page.Items), 3)
+				got = append(got, page.Items...)
+				pages++
+				if page.NextCursor == "" {
+					break
+				}
+				q.Cursor = page.NextCursor
+			}
+			assert.Equal(t, 3, pages)
+			require.Len(t, got, N)
+			for i, p := range got {
+				want := i
+				if order == store.OrderDesc {
+					want = N - 1 - i
+				}
+				assert.Equal(t, Pred(want), p)
+			}
+		})
+	}
+}
+
+func testListRejectsInvalidQuery(t *testing.T, newStore Factory) {
+	s := newStore(t)
+	ctx := context.Background()
+
+	for _, q := range []store.Query{
+		{Cursor: "%%%"},
+		{Order: "sideways"},
+		{Limit: -1},
+		{Kind: "guess"},
+	} {
+		_, err := s.List(ctx, q)
+		assert.ErrorIs(t, err, store.ErrInvalidQuery)
+	}
+}
+
+func testAppendDuplicateIDFails(t *testing.T, newStore Factory) {
+	s := newStore(t)
+	ctx := context.Background()
+
+	require.NoError(t, s.Append(ctx, Pred(1)))
+	dup := Pred(1)
+	dup.FEPods = 99
+	assert.ErrorIs(t, s.Append(ctx, dup), store.ErrExists)
+
+	got, err := s.Get(ctx, dup.ID)
+	require.NoError(t, err)
+	assert.Equal(t, Pred(1), got, "a rejected append must not overwrite the stored prediction")
+}
+
+func testGetAndDelete(t *testing.T, newStore Factory) {
+	s := newStore(t)
+	ctx := context.Background()
+	for i := 0; i < 3; i++ {
+		require.NoError(t, s.Append(ctx, Pred(i)))
+	}
+
+	got, err := s.Get(ctx, Pred(1).ID)
+	require.NoError(t, err)
+	assert.Equal(t, Pred(1), got)
+
+	require.NoError(t, s.Delete(ctx, Pred(0).ID))
+	_, err = s.Get(ctx, Pred(0).ID)
+	assert.ErrorIs(t, err, store.ErrNotFound)
+
+	// Remaining predictions are still reachable by ID and listed in order.
+	got, err = s.Get(ctx, Pred(2).ID)
+	require.NoError(t, err)
+	assert.Equal(t, Pred(2), got)
+	all, err := All(ctx, s)
+	require.NoError(t, err)
+	assert.Equal(t, []store.Prediction{Pred(1), Pred(2)}, all)
+
+	// A deleted ID can be reused.
+	require.NoError(t, s.Append(ctx, Pred(0)))
+	got, err = s.Get(ctx, Pred(0).ID)
+	require.NoError(t, err)
+	assert.Equal(t, Pred(0), got)
+}
+
+func testGetAndDeleteMissing(t *testing.T, newStore Factory) {
+	s := newStore(t)
+	ctx := context.Background()
+
+	_, err := s.Get(ctx, "missing")
+	assert.ErrorIs(t, err, store.ErrNotFound)
+	assert.ErrorIs(t, s.Delete(ctx, "missing"), store.ErrNotFound)
+}
+
+func testUpsertByNaturalKey(t *testing.T, newStore Factory) {
+	s := newStore(t)
+	ctx := context.Background()
+	day := time.Date(2025, 11, 28, 0, 0, 0, 0, time.UTC)
+
+	actual := Pred(1)
+	actual.ID = "ignored"
+	actual.Timestamp = day
+	actual.Source = store.SourceSheet
+	actual.Kind = store.KindActual
+	require.NoError(t, s.Upsert(ctx, actual))
+
+	// Same source, kind and day: replaces the first record.
+	updated := actual
+	updated.Timestamp = day.Add(13 * time.Hour)
+	updated.FEPods = 42
+	require.NoError(t, s.Upsert(ctx, updated))
+
+	// Different kind on the same day: a separate record.
+	predicted := actual
+	predicted.Kind = store.KindPredicted
+	require.NoError(t, s.Upsert(ctx, predicted))
+
+	all, err := All(ctx, s)
+	require.NoError(t, err)
+	require.Len(t, all, 2)
+
+	id := store.KeyOf(actual).ID()
+	got, err := s.Get(ctx, id)
+	require.NoError(t, err)
+	updated.ID = id
+	assert.Equal(t, updated, got)
+
+	got, err = s.Get(ctx, store.KeyOf(predicted).ID())
+	require.NoError(t, err)
+	assert.Equal(t, store.KindPredicted, got.Kind)
+	assert.NotEqual(t, id, got.ID)
+}
+
+func testUpsertRequiresSource(t *testing.T, newStore Factory) {
+	s := newStore(t)
+	assert.ErrorIs(t, s.Upsert(context.Background(), Pred(1)), store.ErrNoSource)
+}
+
+func testAppendBatch(t *testing.T, newStore Factory) {
+	s := newStore(t)
+	ctx := context.Background()
+
+	require.NoError(t, s.AppendBatch(ctx, nil))
+	require.NoError(t, s.Append(ctx, Pred(0)))
+	require.NoError(t, s.AppendBatch(ctx, []store.Prediction{Pred(1), Pred(2), Pred(3)}))
+
+	all, err := All(ctx, s)
+	require.NoError(t, err)
+	assert.Equal(t, []store.Prediction{Pred(0), Pred(1), Pred(2), Pred(3)}, all)
+
+	got, err := s.Get(ctx, Pred(2).ID)
+	require.NoError(t, err)
+	assert.Equal(t, Pred(2), got)
+}
+
+func testAppendBatchIsAllOrNothing(t *testing.T, newStore Factory) {
+	s := newStore(t)
+	ctx := context.Background()
+	require.NoError(t, s.Append(ctx, Pred(0)))
+
+	// Collides with a stored ID.
+	err := s.AppendBatch(ctx, []store.Prediction{Pred(1), Pred(0)})
+	assert.ErrorIs(t, err, store.ErrExists)
+	// Repeats an ID within the batch.
+	err = s.AppendBatch(ctx, []store.Prediction{Pred(2), Pred(3), Pred(2)})
+	assert.ErrorIs(t, err, store.ErrExists)
+
+	all, err := All(ctx, s)
+	require.NoError(t, err)
+	assert.Equal(t, []store.Prediction{Pred(0)}, all, "failed batches must not store anything")
+}